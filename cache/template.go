@@ -5,24 +5,27 @@ import (
 )
 
 type Template struct {
-	SegmentsCache maps.Simple
-	Segments      *maps.Concurrent
-	Var           maps.Simple
-	ShellVersion  string
-	AbsolutePWD   string
-	PSWD          string
-	UserName      string
-	HostName      string
-	PWD           string
-	Shell         string
-	Folder        string
-	OS            string
-	Code          int
-	PromptCount   int
-	SHLVL         int
-	Jobs          int
-	WSL           bool
-	Root          bool
+	SegmentsCache      maps.Simple
+	Segments           *maps.Concurrent
+	Var                maps.Simple
+	ShellVersion       string
+	AbsolutePWD        string
+	PSWD               string
+	UserName           string
+	HostName           string
+	PWD                string
+	Shell              string
+	Folder             string
+	OS                 string
+	TerminalBackground string
+	PaletteName        string
+	Code               int
+	PromptCount        int
+	SHLVL              int
+	Jobs               int
+	WSL                bool
+	IsDarkBackground   bool
+	Root               bool
 }
 
 func (t *Template) AddSegmentData(key string, value any) {
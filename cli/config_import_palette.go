@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/config"
+	"github.com/LNKLEO/OMP/shell"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	themeFormat  string
+	paletteWrite bool
+)
+
+// importPaletteCmd represents the import-palette command
+var importPaletteCmd = &cobra.Command{
+	Use:   "import-palette [theme file]",
+	Short: "Import a palette from a terminal theme",
+	Long: `Import a palette from a terminal theme.
+
+Supported formats are iTerm2 (.itermcolors), Windows Terminal color schemes (.json),
+base16/base24 schemes (.yaml), Alacritty (.toml, .yaml) and kitty themes (.conf).
+The format is derived from the file, use --format to override it.
+
+The 16 ANSI colors are mapped to the palette keys black, red, green, yellow, blue, magenta, cyan, white,
+darkGray, lightRed, lightGreen, lightYellow, lightBlue, lightMagenta, lightCyan and lightWhite.
+When the theme defines them, background, foreground, cursor and selection are added as well.
+
+Example usage:
+
+> oh-my-posh config import-palette ~/themes/Dracula.itermcolors
+
+Prints the palette in the format of your config.
+
+> oh-my-posh config import-palette ~/themes/Dracula.itermcolors --config ~/myconfig.omp.json --write
+
+Adds the palette to your config, existing colors with the same key are replaced.
+A backup of the current config can be found at ~/myconfig.omp.json.bak.
+
+To keep the palette in sync with your terminal theme instead, set palette_import in your config
+to the path of the theme file.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		configFile := config.Path(configFlag)
+		cfg := config.Load(configFile, shell.GENERIC, false)
+
+		// only print the imported colors
+		if !paletteWrite {
+			cfg.Palette = nil
+		}
+
+		if err := cfg.ImportPalette(args[0], color.ThemeFormat(themeFormat)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if !paletteWrite {
+			format := cfg.Format
+			if len(format) == 0 {
+				format = config.JSON
+			}
+
+			fmt.Print(config.ExportPalette(cfg.Palette, format))
+			return
+		}
+
+		cfg.Backup()
+		cfg.Write(cfg.Format)
+	},
+}
+
+func init() {
+	importPaletteCmd.Flags().StringVarP(&themeFormat, "format", "f", "", "the theme format: iterm2, windows-terminal, base16, alacritty or kitty")
+	importPaletteCmd.Flags().BoolVarP(&paletteWrite, "write", "w", false, "write the palette to the config file")
+	configCmd.AddCommand(importPaletteCmd)
+}
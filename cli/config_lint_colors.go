@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/config"
+	"github.com/LNKLEO/OMP/runtime"
+	"github.com/LNKLEO/OMP/shell"
+	"github.com/LNKLEO/OMP/template"
+	"github.com/LNKLEO/OMP/terminal"
+
+	"github.com/spf13/cobra"
+)
+
+var minimumContrast float64
+
+// lintColorsCmd represents the lint-colors command
+var lintColorsCmd = &cobra.Command{
+	Use:   "lint-colors",
+	Short: "Validate the readability of your config's colors",
+	Long: `Validate the readability of your config's colors.
+
+Computes the WCAG contrast ratio of every segment's foreground on its background and lists
+the ones below the minimum. Transparent backgrounds are compared to the terminal_background.
+Colors set using templates or keywords are only known at runtime, use the contrast setting
+in your config to validate or adjust those.
+
+Example usage:
+
+> oh-my-posh config lint-colors --config ~/myconfig.omp.json --minimum 7
+
+Lists the segments that don't meet the WCAG AAA level for normal text.`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		configFile := config.Path(configFlag)
+		cfg := config.Load(configFile, shell.GENERIC, false)
+
+		flags := &runtime.Flags{
+			Config:                  configFile,
+			QueryTerminalBackground: cfg.CanQueryTerminalBackground(),
+		}
+
+		env := &runtime.Terminal{}
+		env.Init(flags)
+		defer env.Close()
+
+		template.Init(env, cfg.Var)
+
+		cfg.ConfigureTerminal(env, "")
+
+		minimum := minimumContrast
+		if minimum <= 1 && cfg.Contrast != nil {
+			minimum = cfg.Contrast.Minimum
+		}
+
+		if minimum <= 1 {
+			minimum = color.DefaultMinimumContrast
+		}
+
+		var failed int
+
+		for _, contrast := range cfg.ColorContrasts(terminal.Colors.Resolve) {
+			if contrast.Ratio >= minimum {
+				continue
+			}
+
+			failed++
+			fmt.Printf("%s: %s on %s has a contrast ratio of %s\n", contrast.Segment, contrast.Foreground, contrast.Background, color.FormatRatio(contrast.Ratio))
+		}
+
+		if failed == 0 {
+			fmt.Printf("all colors meet the minimum contrast ratio of %s\n", color.FormatRatio(minimum))
+			return
+		}
+
+		fmt.Printf("\n%d segment(s) below the minimum contrast ratio of %s\n", failed, color.FormatRatio(minimum))
+		os.Exit(1)
+	},
+}
+
+func init() {
+	lintColorsCmd.Flags().Float64VarP(&minimumContrast, "minimum", "m", 0, "the minimum contrast ratio, defaults to 4.5")
+	configCmd.AddCommand(lintColorsCmd)
+}
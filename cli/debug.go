@@ -41,11 +41,12 @@ func createDebugCmd() *cobra.Command {
 			cfg := config.Load(configFile, sh, false)
 
 			flags := &runtime.Flags{
-				Config: configFile,
-				Debug:  true,
-				PWD:    pwd,
-				Shell:  sh,
-				Plain:  plain,
+				Config:                  configFile,
+				Debug:                   true,
+				PWD:                     pwd,
+				Shell:                   sh,
+				Plain:                   plain,
+				QueryTerminalBackground: cfg.CanQueryTerminalBackground(),
 			}
 
 			env := &runtime.Terminal{}
@@ -59,8 +60,7 @@ func createDebugCmd() *cobra.Command {
 			}()
 
 			terminal.Init(shell.GENERIC)
-			terminal.BackgroundColor = cfg.TerminalBackground.ResolveTemplate()
-			terminal.Colors = cfg.MakeColors(env)
+			cfg.ConfigureTerminal(env, "")
 			terminal.Plain = plain
 
 			eng := &prompt.Engine{
@@ -28,6 +28,7 @@ var (
 	plain        bool
 	noStatus     bool
 	column       int
+	colorProfile string
 )
 
 // printCmd represents the prompt command
@@ -76,6 +77,7 @@ func createPrintCmd() *cobra.Command {
 				Cleared:       cleared,
 				NoExitCode:    noStatus,
 				Column:        column,
+				ColorProfile:  colorProfile,
 				JobCount:      jobCount,
 				IsPrimary:     args[0] == prompt.PRIMARY,
 				SaveCache:     saveCache,
@@ -127,6 +129,7 @@ func createPrintCmd() *cobra.Command {
 	printCmd.Flags().BoolVar(&eval, "eval", false, "output the prompt for eval")
 	printCmd.Flags().IntVar(&column, "column", 0, "the column position of the cursor")
 	printCmd.Flags().IntVar(&jobCount, "job-count", 0, "number of background jobs")
+	printCmd.Flags().StringVar(&colorProfile, "color-profile", "", "color support of the terminal: auto, truecolor, 256, 16 or none")
 	printCmd.Flags().BoolVar(&saveCache, "save-cache", false, "save updated cache to file")
 
 	// Hide flags that are for internal use only.
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/prompt"
+	"github.com/LNKLEO/OMP/render"
+	"github.com/LNKLEO/OMP/runtime"
+	"github.com/LNKLEO/OMP/shell"
+	"github.com/LNKLEO/OMP/terminal"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderFormat        string
+	renderOutput        string
+	renderTheme         string
+	renderPWD           string
+	renderFontSize      float64
+	renderExecutionTime float64
+	renderStatus        int
+	renderWidth         int
+	renderMocks         []string
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render the prompt to HTML, SVG or PNG",
+	Long: `Render the prompt to HTML, SVG or PNG.
+
+Runs the primary prompt for your config and draws the result, to preview a theme without installing it.
+The context can be set using --pwd, --status, --execution-time and --terminal-width, segment data
+can be mocked with --mock, which skips executing the segment.
+
+HTML uses the Nerd Font installed on the machine that displays it. SVG and PNG draw the powerline
+separators as shapes and the Font Awesome icons of Nerd Fonts using an embedded font. SVG leaves the
+other icons to the installed Nerd Font, PNG shows them as a placeholder box.
+
+Example usage:
+
+> oh-my-posh render --config ~/myconfig.omp.json --output prompt.svg
+
+Renders the prompt in the current directory to prompt.svg.
+
+> oh-my-posh render --config ~/myconfig.omp.json --pwd ~/code/app --status 1 --mock 'git={"HEAD":"main","Ahead":2}' --output prompt.png
+
+Renders the prompt in ~/code/app after a failed command, with the git segment on the main branch.
+
+> oh-my-posh render --config ~/myconfig.omp.json --theme ~/themes/Dracula.itermcolors --format html
+
+Prints the prompt as HTML, using the colors of a terminal theme (see config import-palette for the supported formats).`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		format := render.Format(strings.ToLower(renderFormat))
+		if len(format) == 0 {
+			format = render.Format(strings.TrimPrefix(strings.ToLower(filepath.Ext(renderOutput)), "."))
+		}
+
+		if len(format) == 0 {
+			format = render.HTML
+		}
+
+		mocks := make(map[string]string, len(renderMocks))
+		for _, mock := range renderMocks {
+			segment, data, OK := strings.Cut(mock, "=")
+			if !OK {
+				fmt.Printf("invalid mock %s, expected <segment>=<json>\n", mock)
+				os.Exit(2)
+			}
+
+			mocks[segment] = data
+		}
+
+		// always render for the generic shell, the prompt escapes of the other shells would end up in the output
+		flags := &runtime.Flags{
+			Config:        configFlag,
+			PWD:           renderPWD,
+			ErrorCode:     renderStatus,
+			ExecutionTime: renderExecutionTime,
+			TerminalWidth: max(renderWidth, 1),
+			Shell:         shell.GENERIC,
+			ColorProfile:  string(color.TrueColor),
+			Type:          prompt.PRIMARY,
+			IsPrimary:     true,
+			Mocks:         mocks,
+		}
+
+		eng := prompt.New(flags)
+		defer eng.Env.Close()
+
+		// the renderer supports every style and glyph, regardless of the terminal we're running in
+		terminal.ExtendedUnderlines = true
+		terminal.Glyphs = terminal.NerdGlyphs
+		terminal.OverrideGlyphs(string(eng.Config.Glyphs))
+
+		screen := render.NewScreen(eng.Primary(), flags.TerminalWidth)
+
+		options := &render.Options{
+			Title:    strings.TrimSuffix(filepath.Base(flags.Config), filepath.Ext(flags.Config)),
+			FontSize: renderFontSize,
+		}
+
+		if len(renderTheme) != 0 {
+			theme, err := render.ThemeFromFile(renderTheme)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			options.Theme = theme
+		}
+
+		var result bytes.Buffer
+		if err := render.Render(&result, screen, format, options); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(renderOutput) == 0 {
+			_, _ = os.Stdout.Write(result.Bytes())
+			return
+		}
+
+		if err := os.WriteFile(cleanOutputPath(renderOutput), result.Bytes(), 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringVarP(&renderFormat, "format", "f", "", "the output format: html, svg or png, defaults to the extension of the output file or html")
+	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "", "the file to write to, defaults to stdout")
+	renderCmd.Flags().StringVar(&renderTheme, "theme", "", "a terminal theme file for the default and ANSI colors")
+	renderCmd.Flags().Float64Var(&renderFontSize, "font-size", 16, "the font size in pixels")
+	renderCmd.Flags().StringArrayVar(&renderMocks, "mock", nil, "segment data as <segment type or alias>=<json>, can be repeated")
+	renderCmd.Flags().StringVar(&renderPWD, "pwd", "", "current working directory")
+	renderCmd.Flags().IntVar(&renderStatus, "status", 0, "last known status code")
+	renderCmd.Flags().Float64Var(&renderExecutionTime, "execution-time", 0, "timing of the last command")
+	renderCmd.Flags().IntVarP(&renderWidth, "terminal-width", "w", 100, "width of the terminal")
+	RootCmd.AddCommand(renderCmd)
+}
@@ -14,8 +14,6 @@ import (
 	"github.com/LNKLEO/OMP/template"
 )
 
-var TrueColor = true
-
 // String is the interface that wraps ToColor method.
 //
 // ToColor gets the ANSI color code for a given color string.
@@ -190,12 +188,9 @@ func (d *Defaults) SetAccentColor(env runtime.Environment, defaultColor Ansi) {
 		return
 	}
 
-	foreground := color.RGB(rgb.R, rgb.G, rgb.B, false)
-	background := color.RGB(rgb.R, rgb.G, rgb.B, true)
-
 	d.accent = &Set{
-		Foreground: Ansi(foreground.String()),
-		Background: Ansi(background.String()),
+		Foreground: downsample(*rgb, false),
+		Background: downsample(*rgb, true),
 	}
 
 	env.Session().Set("accent_color", d.accent.String(), cache.INFINITE)
@@ -243,10 +238,24 @@ func (d *Defaults) ToAnsi(ansiColor Ansi, isBackground bool) Ansi {
 		return emptyColor
 	}
 
+	if CurrentProfile == NoColor {
+		return emptyColor
+	}
+
 	if ansiColor.IsTransparent() {
 		return ansiColor
 	}
 
+	if ansiColor.IsFunction() {
+		resolved, err := Evaluate(ansiColor, nil)
+		if err != nil {
+			log.Error(err)
+			return emptyColor
+		}
+
+		ansiColor = resolved
+	}
+
 	if ansiColor == Accent {
 		if d.accent == nil {
 			return emptyColor
@@ -272,17 +281,16 @@ func (d *Defaults) ToAnsi(ansiColor Ansi, isBackground bool) Ansi {
 			return emptyColor
 		}
 
+		if CurrentProfile == ANSI16 && val > 15 {
+			return to16(nearest16(xterm256[val]), isBackground)
+		}
+
 		c256 := color.C256(uint8(val), isBackground)
 		return Ansi(c256.String())
 	}
 
-	style := color.HEX(colorString, isBackground)
-	if !style.IsEmpty() {
-		if TrueColor {
-			return Ansi(style.String())
-		}
-
-		return Ansi(style.C256().String())
+	if rgb, OK := hexToRGB(colorString); OK {
+		return downsample(rgb, isBackground)
 	}
 
 	if colorInt, err := strconv.ParseInt(colorString, 10, 8); err == nil {
@@ -295,6 +303,10 @@ func (d *Defaults) ToAnsi(ansiColor Ansi, isBackground bool) Ansi {
 }
 
 func (d *Defaults) Resolve(colorString Ansi) (Ansi, error) {
+	if colorString.IsFunction() {
+		return Evaluate(colorString, nil)
+	}
+
 	return colorString, nil
 }
 
@@ -0,0 +1,124 @@
+package color
+
+import (
+	"fmt"
+
+	"github.com/LNKLEO/OMP/log"
+)
+
+// ContrastMode defines what happens when a foreground isn't readable on its background
+type ContrastMode string
+
+const (
+	// WarnContrast logs the colors that don't meet the minimum contrast ratio
+	WarnContrast ContrastMode = "warn"
+	// AdjustContrast changes the lightness of the foreground until the minimum contrast ratio is met
+	AdjustContrast ContrastMode = "adjust"
+
+	// DefaultMinimumContrast is the WCAG AA level for normal text
+	DefaultMinimumContrast = 4.5
+)
+
+type Contrast struct {
+	Mode    ContrastMode `json:"mode,omitempty" toml:"mode,omitempty"`
+	Minimum float64      `json:"minimum,omitempty" toml:"minimum,omitempty"`
+}
+
+func (c *Contrast) minimum() float64 {
+	if c.Minimum <= 1 {
+		return DefaultMinimumContrast
+	}
+
+	return c.Minimum
+}
+
+// Check validates the contrast of a resolved foreground and background color pair. In adjust mode,
+// the returned foreground meets the minimum ratio when possible. Colors that can't be derived,
+// like keywords, are left as is. An empty background falls back to the terminal background.
+func (c *Contrast) Check(foreground, background Ansi, resolve func(Ansi) (Ansi, error)) Ansi {
+	if c == nil || (c.Mode != WarnContrast && c.Mode != AdjustContrast) {
+		return foreground
+	}
+
+	if background.IsClear() {
+		background = TerminalBackground
+	}
+
+	if foreground.IsClear() || background.IsClear() {
+		return foreground
+	}
+
+	ratio, err := Ratio(foreground, background, resolve)
+	if err != nil || ratio >= c.minimum() {
+		return foreground
+	}
+
+	if c.Mode == WarnContrast {
+		log.Debugf("contrast ratio of %s on %s is %.2f, below %.2f", foreground, background, ratio, c.minimum())
+		return foreground
+	}
+
+	readable, err := Readable(foreground, background, c.minimum(), resolve)
+	if err != nil {
+		return foreground
+	}
+
+	log.Debugf("adjusted %s to %s for a readable contrast on %s", foreground, readable, background)
+
+	return readable
+}
+
+// Ratio computes the WCAG 2 contrast ratio between two colors
+func Ratio(foreground, background Ansi, resolve func(Ansi) (Ansi, error)) (float64, error) {
+	fg, err := toRGB(foreground, resolve)
+	if err != nil {
+		return 0, err
+	}
+
+	bg, err := toRGB(background, resolve)
+	if err != nil {
+		return 0, err
+	}
+
+	return ContrastRatio(fg, bg), nil
+}
+
+// Readable changes the lightness of the foreground, towards white on dark backgrounds and towards
+// black on light ones, until the contrast ratio meets the minimum. When that's impossible,
+// the color that's the furthest away is returned.
+func Readable(foreground, background Ansi, minimum float64, resolve func(Ansi) (Ansi, error)) (Ansi, error) {
+	fg, err := toRGB(foreground, resolve)
+	if err != nil {
+		return "", err
+	}
+
+	bg, err := toRGB(background, resolve)
+	if err != nil {
+		return "", err
+	}
+
+	if ContrastRatio(fg, bg) >= minimum {
+		return foreground, nil
+	}
+
+	step := 0.02
+	if ContrastRatio(RGB{255, 255, 255}, bg) < ContrastRatio(RGB{}, bg) {
+		step = -step
+	}
+
+	h, s, l := fg.hsl()
+
+	for {
+		l = clamp(l + step)
+		candidate := fromHSL(h, s, l)
+
+		if ContrastRatio(candidate, bg) >= minimum || l == 0 || l == 1 {
+			return candidate.Hex(), nil
+		}
+	}
+}
+
+// FormatRatio formats the ratio the way WCAG does
+func FormatRatio(ratio float64) string {
+	return fmt.Sprintf("%.2f:1", ratio)
+}
@@ -0,0 +1,152 @@
+package color
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gookit/color"
+)
+
+// the xterm defaults for the 16 basic colors, terminals often
+// use a different palette but we need a deterministic reference
+var ansi16 = [16]RGB{
+	{0, 0, 0},
+	{205, 0, 0},
+	{0, 205, 0},
+	{205, 205, 0},
+	{0, 0, 238},
+	{205, 0, 205},
+	{0, 205, 205},
+	{229, 229, 229},
+	{127, 127, 127},
+	{255, 0, 0},
+	{0, 255, 0},
+	{255, 255, 0},
+	{92, 92, 255},
+	{255, 0, 255},
+	{0, 255, 255},
+	{255, 255, 255},
+}
+
+type lab struct {
+	L, A, B float64
+}
+
+// downsample converts a 24-bit color to the closest color available in the current profile.
+func downsample(rgb RGB, isBackground bool) Ansi {
+	switch CurrentProfile {
+	case NoColor:
+		return emptyColor
+	case ANSI16:
+		return to16(nearest16(rgb), isBackground)
+	case ANSI256:
+		return Ansi(color.C256(nearest256(rgb), isBackground).String())
+	default:
+		return Ansi(color.RGB(rgb.R, rgb.G, rgb.B, isBackground).String())
+	}
+}
+
+func to16(index uint8, isBackground bool) Ansi {
+	code := 30 + int(index)
+	if index >= 8 {
+		code = 90 + int(index) - 8
+	}
+
+	if isBackground {
+		code += 10
+	}
+
+	return Ansi(fmt.Sprint(code))
+}
+
+func nearest16(rgb RGB) uint8 {
+	return nearest(rgb, ansi16[:], 0)
+}
+
+// nearest256 only matches the color cube and the grayscale ramp,
+// the first 16 colors are usually redefined by the terminal's theme.
+func nearest256(rgb RGB) uint8 {
+	return nearest(rgb, xterm256[16:], 16)
+}
+
+// nearest returns the index of the perceptually closest color, using the
+// euclidean distance in the CIELAB color space. The first match wins on a tie.
+func nearest(rgb RGB, palette []RGB, offset int) uint8 {
+	target := rgb.lab()
+
+	index := 0
+	distance := math.MaxFloat64
+
+	for i, candidate := range palette {
+		other := candidate.lab()
+		d := (target.L-other.L)*(target.L-other.L) + (target.A-other.A)*(target.A-other.A) + (target.B-other.B)*(target.B-other.B)
+
+		if d < distance {
+			index = i
+			distance = d
+		}
+	}
+
+	return uint8(index + offset)
+}
+
+var xterm256 = func() [256]RGB {
+	var palette [256]RGB
+
+	copy(palette[:16], ansi16[:])
+
+	levels := [6]uint8{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ {
+		palette[16+i] = RGB{levels[i/36], levels[(i/6)%6], levels[i%6]}
+	}
+
+	for i := 0; i < 24; i++ {
+		gray := uint8(8 + i*10)
+		palette[232+i] = RGB{gray, gray, gray}
+	}
+
+	return palette
+}()
+
+func (rgb RGB) lab() lab {
+	linear := func(c uint8) float64 {
+		v := float64(c) / 255
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	r, g, b := linear(rgb.R), linear(rgb.G), linear(rgb.B)
+
+	// sRGB to XYZ, relative to the D65 white point
+	x := (0.4124*r + 0.3576*g + 0.1805*b) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := (0.0193*r + 0.1192*g + 0.9505*b) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389.0 {
+			return math.Cbrt(t)
+		}
+
+		return (24389.0/27.0*t + 16) / 116
+	}
+
+	fx, fy, fz := f(x), f(y), f(z)
+
+	return lab{
+		L: 116*fy - 16,
+		A: 500 * (fx - fy),
+		B: 200 * (fy - fz),
+	}
+}
+
+func hexToRGB(hex string) (RGB, bool) {
+	values := color.HexToRgb(hex)
+	if len(values) != 3 {
+		return RGB{}, false
+	}
+
+	return RGB{uint8(values[0]), uint8(values[1]), uint8(values[2])}, true
+}
@@ -0,0 +1,409 @@
+package color
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TerminalBackground is the color alpha blending happens over when no background is given
+var TerminalBackground Ansi
+
+const (
+	darkenFunction     = "darken"
+	lightenFunction    = "lighten"
+	mixFunction        = "mix"
+	alphaBlendFunction = "alpha-blend"
+	blendFunction      = "blend"
+	contrastFunction   = "contrast"
+
+	black = "#000000"
+	white = "#ffffff"
+)
+
+var (
+	colorFunctions = []string{darkenFunction, lightenFunction, mixFunction, alphaBlendFunction, blendFunction, contrastFunction}
+
+	ansi16Names = map[Ansi]int{
+		"black":        0,
+		"red":          1,
+		"green":        2,
+		"yellow":       3,
+		"blue":         4,
+		"magenta":      5,
+		"cyan":         6,
+		"white":        7,
+		"darkGray":     8,
+		"lightRed":     9,
+		"lightGreen":   10,
+		"lightYellow":  11,
+		"lightBlue":    12,
+		"lightMagenta": 13,
+		"lightCyan":    14,
+		"lightWhite":   15,
+	}
+)
+
+// IsFunction checks whether the color is derived from other colors, like `darken(p:blue, 20)`.
+func (c Ansi) IsFunction() bool {
+	name, _, found := strings.Cut(c.String(), "(")
+	if !found || !strings.HasSuffix(c.String(), ")") {
+		return false
+	}
+
+	name = strings.TrimSpace(name)
+
+	for _, function := range colorFunctions {
+		if name == function {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Evaluate computes a derived color as a hex color, using resolve to look up
+// the colors it's derived from. The following functions are available:
+//   - darken(color, percentage) and lighten(color, percentage) change the lightness
+//   - mix(color, color, weight) mixes in the weight of the second color
+//   - alpha-blend(color, opacity[, background]) blends the color at the opacity over the background, or the terminal background,
+//     blend is an alias
+//   - contrast(color[, dark, light]) picks the most readable text color on the given color, black and white by default
+//
+// Percentages range from 0 to 100, weights and opacities are fractions from 0 to 1.
+// Any amount can be given as a percentage using the % sign, like `mix(a, b, 25%)`.
+func Evaluate(expression Ansi, resolve func(Ansi) (Ansi, error)) (Ansi, error) {
+	name, args, err := parseFunction(expression.String())
+	if err != nil {
+		return "", err
+	}
+
+	color := func(index int) (RGB, error) {
+		if index >= len(args) {
+			return RGB{}, fmt.Errorf("%s: missing color argument", name)
+		}
+
+		return toRGB(Ansi(args[index]), resolve)
+	}
+
+	// amount returns the argument as a fraction, a number without a % sign is
+	// a percentage when percent is set and a fraction otherwise
+	amount := func(index int, fallback float64, percent bool) (float64, error) {
+		if index >= len(args) {
+			return fallback, nil
+		}
+
+		value := strings.TrimSpace(args[index])
+
+		number, hasPercent := strings.CutSuffix(value, "%")
+		percent = percent || hasPercent
+
+		result, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s: invalid amount %s", name, value)
+		}
+
+		expected := "0 to 1"
+		if percent {
+			result /= 100
+			expected = "0 to 100"
+		}
+
+		if result < 0 || result > 1 {
+			return 0, fmt.Errorf("%s: amount %s out of range, expected %s", name, value, expected)
+		}
+
+		return result, nil
+	}
+
+	base, err := color(0)
+	if err != nil {
+		return "", err
+	}
+
+	var result RGB
+
+	switch name {
+	case darkenFunction, lightenFunction:
+		// like Sass, the amount is an absolute change in lightness
+		change, err := amount(1, 0.1, true)
+		if err != nil {
+			return "", err
+		}
+
+		if name == darkenFunction {
+			change = -change
+		}
+
+		h, s, l := base.hsl()
+		result = fromHSL(h, s, clamp(l+change))
+	case mixFunction:
+		other, err := color(1)
+		if err != nil {
+			return "", err
+		}
+
+		weight, err := amount(2, 0.5, false)
+		if err != nil {
+			return "", err
+		}
+
+		result = base.mix(other, weight)
+	case alphaBlendFunction, blendFunction:
+		alpha, err := amount(1, 1, false)
+		if err != nil {
+			return "", err
+		}
+
+		background := RGB{}
+
+		switch {
+		case len(args) > 2:
+			if background, err = color(2); err != nil {
+				return "", err
+			}
+		case len(TerminalBackground) != 0:
+			if rgb, err := toRGB(TerminalBackground, resolve); err == nil {
+				background = rgb
+			}
+		}
+
+		result = background.mix(base, alpha)
+	case contrastFunction:
+		dark, light := Ansi(black), Ansi(white)
+
+		if len(args) > 1 {
+			dark = Ansi(args[1])
+		}
+
+		if len(args) > 2 {
+			light = Ansi(args[2])
+		}
+
+		darkRGB, err := toRGB(dark, resolve)
+		if err != nil {
+			return "", err
+		}
+
+		lightRGB, err := toRGB(light, resolve)
+		if err != nil {
+			return "", err
+		}
+
+		result = lightRGB
+		if ContrastRatio(base, darkRGB) >= ContrastRatio(base, lightRGB) {
+			result = darkRGB
+		}
+	}
+
+	return result.Hex(), nil
+}
+
+func parseFunction(expression string) (string, []string, error) {
+	expression = strings.TrimSpace(expression)
+
+	name, rest, found := strings.Cut(expression, "(")
+	if !found || !strings.HasSuffix(rest, ")") {
+		return "", nil, fmt.Errorf("invalid color function: %s", expression)
+	}
+
+	rest = strings.TrimSuffix(rest, ")")
+
+	// split on the commas outside of nested functions
+	var args []string
+	var depth, start int
+
+	for i, char := range rest {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth != 0 {
+				continue
+			}
+
+			args = append(args, strings.TrimSpace(rest[start:i]))
+			start = i + 1
+		}
+	}
+
+	if depth != 0 {
+		return "", nil, fmt.Errorf("unbalanced parentheses in color function: %s", expression)
+	}
+
+	if last := strings.TrimSpace(rest[start:]); len(last) != 0 {
+		args = append(args, last)
+	}
+
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("missing arguments in color function: %s", expression)
+	}
+
+	return strings.TrimSpace(name), args, nil
+}
+
+// toRGB resolves a color to its RGB value. Named ANSI colors and
+// the 256 color palette use the xterm defaults.
+func toRGB(value Ansi, resolve func(Ansi) (Ansi, error)) (RGB, error) {
+	value = Ansi(strings.TrimSpace(value.String()))
+
+	if value.IsFunction() {
+		resolved, err := Evaluate(value, resolve)
+		if err != nil {
+			return RGB{}, err
+		}
+
+		value = resolved
+	}
+
+	if resolve != nil {
+		resolved, err := resolve(value)
+		if err != nil {
+			return RGB{}, err
+		}
+
+		value = resolved
+	}
+
+	if value.IsFunction() {
+		return toRGB(value, resolve)
+	}
+
+	if rgb, OK := hexToRGB(value.String()); OK {
+		return rgb, nil
+	}
+
+	if index, OK := ansi16Names[value]; OK {
+		return ansi16[index], nil
+	}
+
+	if index, err := strconv.ParseUint(value.String(), 10, 8); err == nil {
+		return xterm256[index], nil
+	}
+
+	return RGB{}, errors.New("unable to derive a color from " + value.String())
+}
+
+// Hex formats the color as #rrggbb
+func (rgb RGB) Hex() Ansi {
+	return Ansi(fmt.Sprintf("#%02x%02x%02x", rgb.R, rgb.G, rgb.B))
+}
+
+// mix returns the color with weight of the other color mixed in
+func (rgb RGB) mix(other RGB, weight float64) RGB {
+	channel := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*(1-weight) + float64(b)*weight))
+	}
+
+	return RGB{channel(rgb.R, other.R), channel(rgb.G, other.G), channel(rgb.B, other.B)}
+}
+
+func (rgb RGB) hsl() (h, s, l float64) {
+	r, g, b := float64(rgb.R)/255, float64(rgb.G)/255, float64(rgb.B)/255
+
+	high := math.Max(r, math.Max(g, b))
+	low := math.Min(r, math.Min(g, b))
+
+	l = (high + low) / 2
+
+	if high == low {
+		return 0, 0, l
+	}
+
+	delta := high - low
+
+	if l > 0.5 {
+		s = delta / (2 - high - low)
+	} else {
+		s = delta / (high + low)
+	}
+
+	switch high {
+	case r:
+		h = (g - b) / delta
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+
+	return h / 6, s, l
+}
+
+func fromHSL(h, s, l float64) RGB {
+	if s == 0 {
+		gray := uint8(math.Round(l * 255))
+		return RGB{gray, gray, gray}
+	}
+
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+
+	p := 2*l - q
+
+	hue := func(t float64) uint8 {
+		if t < 0 {
+			t++
+		}
+
+		if t > 1 {
+			t--
+		}
+
+		var value float64
+
+		switch {
+		case t < 1.0/6:
+			value = p + (q-p)*6*t
+		case t < 1.0/2:
+			value = q
+		case t < 2.0/3:
+			value = p + (q-p)*(2.0/3-t)*6
+		default:
+			value = p
+		}
+
+		return uint8(math.Round(value * 255))
+	}
+
+	return RGB{hue(h + 1.0/3), hue(h), hue(h - 1.0/3)}
+}
+
+// luminance is the relative luminance as defined by WCAG 2
+func (rgb RGB) luminance() float64 {
+	linear := func(c uint8) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linear(rgb.R) + 0.7152*linear(rgb.G) + 0.0722*linear(rgb.B)
+}
+
+// ContrastRatio returns the WCAG 2 contrast ratio between two colors, from 1 to 21
+func ContrastRatio(a, b RGB) float64 {
+	la, lb := a.luminance(), b.luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func clamp(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
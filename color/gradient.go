@@ -0,0 +1,43 @@
+package color
+
+import (
+	"errors"
+	"math"
+)
+
+// Gradient spreads the color stops evenly over a number of columns
+// and returns the interpolated hex color for each column.
+func Gradient(stops []Ansi, columns int, resolve func(Ansi) (Ansi, error)) ([]Ansi, error) {
+	if len(stops) < 2 {
+		return nil, errors.New("a gradient needs at least two colors")
+	}
+
+	rgbs := make([]RGB, 0, len(stops))
+
+	for _, stop := range stops {
+		rgb, err := toRGB(stop, resolve)
+		if err != nil {
+			return nil, err
+		}
+
+		rgbs = append(rgbs, rgb)
+	}
+
+	colors := make([]Ansi, columns)
+	segments := float64(len(rgbs) - 1)
+
+	for column := range colors {
+		position := 0.0
+		if columns > 1 {
+			position = float64(column) / float64(columns-1)
+		}
+
+		// find the two stops the position is in between
+		scaled := position * segments
+		index := int(math.Min(math.Floor(scaled), segments-1))
+
+		colors[column] = rgbs[index].mix(rgbs[index+1], scaled-float64(index)).Hex()
+	}
+
+	return colors, nil
+}
@@ -0,0 +1,403 @@
+package color
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/jsonutil"
+
+	json "github.com/goccy/go-json"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// ThemeFormat is a terminal theme file format palettes can be imported from
+type ThemeFormat string
+
+const (
+	ITerm2          ThemeFormat = "iterm2"
+	WindowsTerminal ThemeFormat = "windows-terminal"
+	Base16          ThemeFormat = "base16"
+	Alacritty       ThemeFormat = "alacritty"
+	Kitty           ThemeFormat = "kitty"
+
+	// palette keys for the colors that aren't one of the 16 ANSI slots
+	backgroundKey = "background"
+	foregroundKey = "foreground"
+	cursorKey     = "cursor"
+	selectionKey  = "selection"
+)
+
+// ansiSlots are the palette keys of the 16 ANSI colors, using the same
+// names as the color keywords so p:lightRed matches the terminal's lightRed
+var ansiSlots = [16]Ansi{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"darkGray", "lightRed", "lightGreen", "lightYellow", "lightBlue", "lightMagenta", "lightCyan", "lightWhite",
+}
+
+// the names most terminal themes use for the 8 normal colors
+var themeSlots = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// ImportPalette reads the colors of a terminal theme into a palette, mapping the
+// 16 ANSI slots to the color keyword names (black, red, ..., darkGray, lightRed, ...)
+// next to background, foreground, cursor and selection when the theme defines them.
+// When format is empty, it's derived from the file.
+func ImportPalette(file string, format ThemeFormat) (Palette, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(format) == 0 {
+		format = detectThemeFormat(file, data)
+	}
+
+	switch format {
+	case ITerm2:
+		return parseITerm2(data)
+	case WindowsTerminal:
+		return parseWindowsTerminal(data)
+	case Base16:
+		return parseBase16(data)
+	case Alacritty:
+		return parseAlacritty(file, data)
+	case Kitty:
+		return parseKitty(data)
+	default:
+		return nil, fmt.Errorf("unsupported theme format: %s", format)
+	}
+}
+
+func detectThemeFormat(file string, data []byte) ThemeFormat {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".itermcolors":
+		return ITerm2
+	case ".json":
+		return WindowsTerminal
+	case ".toml":
+		return Alacritty
+	case ".yml", ".yaml":
+		// alacritty used YAML before switching to TOML
+		var theme map[string]any
+		if err := yaml.Unmarshal(data, &theme); err == nil {
+			if _, OK := theme["colors"]; OK {
+				return Alacritty
+			}
+		}
+
+		return Base16
+	}
+
+	if bytes.Contains(data, []byte("<plist")) {
+		return ITerm2
+	}
+
+	return Kitty
+}
+
+func parseITerm2(data []byte) (Palette, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root map[string]any
+
+	for root == nil {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid iTerm2 color preset: %w", err)
+		}
+
+		if start, OK := token.(xml.StartElement); OK && start.Name.Local == "dict" {
+			if root, err = parsePlistDict(decoder); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	palette := make(Palette)
+
+	add := func(key Ansi, name string) {
+		components, OK := root[name].(map[string]any)
+		if !OK {
+			return
+		}
+
+		component := func(name string) uint8 {
+			value, _ := strconv.ParseFloat(fmt.Sprint(components[name+" Component"]), 64)
+			return uint8(math.Round(clamp(value) * 255))
+		}
+
+		palette[key] = RGB{component("Red"), component("Green"), component("Blue")}.Hex()
+	}
+
+	for i, slot := range ansiSlots {
+		add(slot, fmt.Sprintf("Ansi %d Color", i))
+	}
+
+	add(backgroundKey, "Background Color")
+	add(foregroundKey, "Foreground Color")
+	add(cursorKey, "Cursor Color")
+	add(selectionKey, "Selection Color")
+
+	return validateImport(palette, ITerm2)
+}
+
+// parsePlistDict reads the values of a property list dict, nested dicts are
+// returned as maps and all other values as their text content
+func parsePlistDict(decoder *xml.Decoder) (map[string]any, error) {
+	dict := make(map[string]any)
+
+	var key string
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid iTerm2 color preset: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.EndElement:
+			if element.Name.Local == "dict" {
+				return dict, nil
+			}
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "key":
+				if err := decoder.DecodeElement(&key, &element); err != nil {
+					return nil, err
+				}
+			case "dict":
+				if dict[key], err = parsePlistDict(decoder); err != nil {
+					return nil, err
+				}
+			default:
+				var value string
+				if err := decoder.DecodeElement(&value, &element); err != nil {
+					return nil, err
+				}
+
+				dict[key] = strings.TrimSpace(value)
+			}
+		}
+	}
+}
+
+// parseWindowsTerminal reads a color scheme, or the first scheme of a settings file
+func parseWindowsTerminal(data []byte) (Palette, error) {
+	data = []byte(jsonutil.StripComments(string(data)))
+
+	var scheme map[string]any
+	if err := json.Unmarshal(data, &scheme); err != nil {
+		return nil, fmt.Errorf("invalid Windows Terminal color scheme: %w", err)
+	}
+
+	if schemes, OK := scheme["schemes"].([]any); OK && len(schemes) != 0 {
+		if first, OK := schemes[0].(map[string]any); OK {
+			scheme = first
+		}
+	}
+
+	value := func(key string) string {
+		text, _ := scheme[key].(string)
+		return text
+	}
+
+	palette := make(Palette)
+
+	for i, name := range themeSlots {
+		// Windows Terminal calls magenta purple
+		if name == "magenta" {
+			name = "purple"
+		}
+
+		palette.set(ansiSlots[i], value(name))
+		palette.set(ansiSlots[i+8], value("bright"+strings.ToUpper(name[:1])+name[1:]))
+	}
+
+	palette.set(backgroundKey, value("background"))
+	palette.set(foregroundKey, value("foreground"))
+	palette.set(cursorKey, value("cursorColor"))
+	palette.set(selectionKey, value("selectionBackground"))
+
+	return validateImport(palette, WindowsTerminal)
+}
+
+// parseBase16 maps a base16 or base24 scheme to the ANSI slots like base16-shell does.
+// Both the classic format and the tinted-theming format with a nested palette are supported.
+func parseBase16(data []byte) (Palette, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base16 scheme: %w", err)
+	}
+
+	scheme := make(map[string]string)
+	for _, doc := range file.Docs {
+		base16Values(doc.Body, scheme)
+	}
+
+	base := func(key string) string {
+		for name, value := range scheme {
+			if strings.EqualFold(name, key) {
+				return value
+			}
+		}
+
+		return ""
+	}
+
+	normal := [8]string{"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05"}
+	bright := [8]string{"base03", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base07"}
+
+	// base24 defines distinct bright colors
+	if len(base("base12")) != 0 {
+		bright = [8]string{"base03", "base12", "base14", "base13", "base16", "base17", "base15", "base07"}
+	}
+
+	palette := make(Palette)
+
+	for i := range normal {
+		palette.set(ansiSlots[i], base(normal[i]))
+		palette.set(ansiSlots[i+8], base(bright[i]))
+	}
+
+	palette.set(backgroundKey, base("base00"))
+	palette.set(foregroundKey, base("base05"))
+	palette.set(cursorKey, base("base05"))
+	palette.set(selectionKey, base("base02"))
+
+	return validateImport(palette, Base16)
+}
+
+// base16Values collects the values of a scheme as written, as unquoted values like 101010 or 010101
+// would be parsed as (octal) numbers otherwise. The nested palette of the tinted-theming format is included.
+func base16Values(node ast.Node, scheme map[string]string) {
+	mapping, OK := node.(ast.MapNode)
+	if !OK {
+		return
+	}
+
+	values := mapping.MapRange()
+	for values.Next() {
+		key := values.Key().GetToken().Value
+
+		switch value := values.Value().(type) {
+		case ast.MapNode:
+			if strings.EqualFold(key, "palette") {
+				base16Values(values.Value(), scheme)
+			}
+		case ast.ScalarNode:
+			scheme[key] = value.GetToken().Value
+		}
+	}
+}
+
+// parseAlacritty reads the colors section of a TOML or (legacy) YAML config
+func parseAlacritty(file string, data []byte) (Palette, error) {
+	var theme struct {
+		Colors struct {
+			Primary   map[string]string `json:"primary" toml:"primary"`
+			Normal    map[string]string `json:"normal" toml:"normal"`
+			Bright    map[string]string `json:"bright" toml:"bright"`
+			Cursor    map[string]string `json:"cursor" toml:"cursor"`
+			Selection map[string]string `json:"selection" toml:"selection"`
+		} `json:"colors" toml:"colors"`
+	}
+
+	var err error
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &theme)
+	default:
+		err = toml.Unmarshal(data, &theme)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid Alacritty config: %w", err)
+	}
+
+	colors := theme.Colors
+	palette := make(Palette)
+
+	for i, name := range themeSlots {
+		palette.set(ansiSlots[i], colors.Normal[name])
+		palette.set(ansiSlots[i+8], colors.Bright[name])
+	}
+
+	palette.set(backgroundKey, colors.Primary["background"])
+	palette.set(foregroundKey, colors.Primary["foreground"])
+	palette.set(cursorKey, colors.Cursor["cursor"])
+	palette.set(selectionKey, colors.Selection["background"])
+
+	return validateImport(palette, Alacritty)
+}
+
+// parseKitty reads the `key value` lines of a kitty theme (or kitty.conf)
+func parseKitty(data []byte) (Palette, error) {
+	keys := map[string]Ansi{
+		"background":           backgroundKey,
+		"foreground":           foregroundKey,
+		"cursor":               cursorKey,
+		"selection_background": selectionKey,
+	}
+
+	for i, slot := range ansiSlots {
+		keys[fmt.Sprintf("color%d", i)] = slot
+	}
+
+	palette := make(Palette)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		if key, OK := keys[fields[0]]; OK {
+			palette.set(key, fields[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return validateImport(palette, Kitty)
+}
+
+// set adds the value as a hex color, themes write them as
+// #rrggbb, 0xrrggbb or rrggbb. Invalid values are ignored.
+func (p Palette) set(key Ansi, value string) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "#")
+	value = strings.TrimPrefix(strings.ToLower(value), "0x")
+
+	rgb, OK := hexToRGB(value)
+	if !OK {
+		return
+	}
+
+	p[key] = rgb.Hex()
+}
+
+func validateImport(palette Palette, format ThemeFormat) (Palette, error) {
+	if len(palette) == 0 {
+		return nil, fmt.Errorf("no colors found in %s theme", format)
+	}
+
+	return palette, nil
+}
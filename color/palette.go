@@ -23,6 +23,19 @@ func (p Palette) ResolveColor(colorName Ansi) (Ansi, error) {
 
 // originalColorName is a pointer to save allocations
 func (p Palette) resolveColor(colorName Ansi, depth int, originalColorName *Ansi) (Ansi, error) {
+	// derived colors can reference palette entries that are derived as well
+	resolve := func(colorName Ansi) (Ansi, error) {
+		if depth > paletteMaxRecursionDepth {
+			return "", &PaletteRecursiveKeyError{Key: *originalColorName, Value: colorName, depth: depth}
+		}
+
+		return p.resolveColor(colorName, depth+1, originalColorName)
+	}
+
+	if colorName.IsFunction() {
+		return Evaluate(colorName, resolve)
+	}
+
 	key, ok := asPaletteKey(colorName)
 	// colorName is not a palette key, return it as is
 	if !ok {
@@ -42,6 +55,10 @@ func (p Palette) resolveColor(colorName Ansi, depth int, originalColorName *Ansi
 		return p.resolveColor(color, depth+1, originalColorName)
 	}
 
+	if color.IsFunction() {
+		return Evaluate(color, resolve)
+	}
+
 	return color, nil
 }
 
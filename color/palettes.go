@@ -3,4 +3,6 @@ package color
 type Palettes struct {
 	List     map[string]Palette `json:"list,omitempty" toml:"list,omitempty"`
 	Template string             `json:"template,omitempty" toml:"template,omitempty"`
+	Schedule *Schedule          `json:"schedule,omitempty" toml:"schedule,omitempty"`
+	Dates    []*DateRange       `json:"dates,omitempty" toml:"dates,omitempty"`
 }
@@ -0,0 +1,165 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/LNKLEO/OMP/log"
+)
+
+// Profile is the amount of colors a terminal can display
+type Profile string
+
+const (
+	// Auto detects the profile from the environment
+	Auto Profile = "auto"
+	// TrueColor supports 24-bit colors
+	TrueColor Profile = "truecolor"
+	// ANSI256 supports the 256 color palette
+	ANSI256 Profile = "256"
+	// ANSI16 supports the 16 basic ANSI colors
+	ANSI16 Profile = "16"
+	// NoColor doesn't write any colors
+	NoColor Profile = "none"
+)
+
+// CurrentProfile is the profile used to convert colors to ANSI sequences
+var CurrentProfile = TrueColor
+
+// ParseProfile validates a profile set by the user, allowing a few common aliases.
+func ParseProfile(value string) (Profile, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "auto", "":
+		return Auto, true
+	case "truecolor", "24bit", "24-bit", "true":
+		return TrueColor, true
+	case "256", "ansi256", "256color":
+		return ANSI256, true
+	case "16", "ansi16", "ansi", "basic":
+		return ANSI16, true
+	case "none", "no", "off", "nocolor":
+		return NoColor, true
+	default:
+		return "", false
+	}
+}
+
+// OverrideProfile replaces the detected profile with the first of the given values that is set and isn't auto.
+func OverrideProfile(values ...string) {
+	for _, value := range values {
+		if len(value) == 0 {
+			continue
+		}
+
+		profile, OK := ParseProfile(value)
+		if !OK {
+			log.Error(fmt.Errorf("unknown color profile: %s", value))
+			continue
+		}
+
+		if profile == Auto {
+			return
+		}
+
+		CurrentProfile = profile
+		return
+	}
+}
+
+// DetectProfile determines the color support of the terminal based on the conventions
+// most tools follow, in order of precedence:
+//   - NO_COLOR disables colors, unless FORCE_COLOR is set
+//   - FORCE_COLOR sets the level (0 to 3)
+//   - CLICOLOR=0 disables colors, CLICOLOR_FORCE enables them
+//   - COLORTERM advertises 24-bit support
+//   - TERM and its terminfo naming conventions (dumb, linux, *-256color, *-direct)
+//   - the terminal program, for terminals that don't advertise their capabilities
+func DetectProfile(getenv func(string) string, program string) Profile {
+	if profile, OK := forcedProfile(getenv("FORCE_COLOR")); OK {
+		return profile
+	}
+
+	if len(getenv("NO_COLOR")) != 0 {
+		return NoColor
+	}
+
+	if force := getenv("CLICOLOR_FORCE"); len(force) == 0 || force == "0" {
+		if getenv("CLICOLOR") == "0" {
+			return NoColor
+		}
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+
+	switch {
+	case term == "dumb":
+		return NoColor
+	case strings.HasSuffix(term, "-direct"), strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"):
+		return TrueColor
+	case term == "xterm-kitty", term == "alacritty", term == "wezterm", strings.HasPrefix(term, "foot"), term == "xterm-ghostty":
+		return TrueColor
+	case term == "linux", strings.HasPrefix(term, "vt"), term == "ansi", term == "cygwin":
+		return ANSI16
+	case strings.Contains(term, "256color"):
+		// tmux and screen only pass through 24-bit colors when COLORTERM says so
+		if !isMultiplexer(term) && isTrueColorProgram(program) {
+			return TrueColor
+		}
+
+		return ANSI256
+	case term == "screen", term == "tmux", strings.HasSuffix(term, "-16color"), strings.HasSuffix(term, "-color"):
+		return ANSI16
+	}
+
+	if program == "Apple_Terminal" {
+		return ANSI256
+	}
+
+	return TrueColor
+}
+
+func forcedProfile(value string) (Profile, bool) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", false
+	case "false":
+		return NoColor, true
+	case "true":
+		return ANSI16, true
+	}
+
+	level, err := strconv.Atoi(value)
+	if err != nil {
+		return ANSI16, true
+	}
+
+	switch {
+	case level <= 0:
+		return NoColor, true
+	case level == 1:
+		return ANSI16, true
+	case level == 2:
+		return ANSI256, true
+	default:
+		return TrueColor, true
+	}
+}
+
+func isMultiplexer(term string) bool {
+	return strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux")
+}
+
+func isTrueColorProgram(program string) bool {
+	switch program {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty", "Windows Terminal":
+		return true
+	default:
+		return false
+	}
+}
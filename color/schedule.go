@@ -0,0 +1,197 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/LNKLEO/OMP/log"
+)
+
+const (
+	defaultSunrise = "07:00"
+	defaultSunset  = "19:00"
+
+	dateLayout    = "2006-01-02"
+	dayLayout     = "01-02"
+	dayTimeLayout = "15:04"
+)
+
+// Schedule switches between a day and a night palette, either at fixed times
+// or at sunrise and sunset for the given location
+type Schedule struct {
+	Latitude  *float64 `json:"latitude,omitempty" toml:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty" toml:"longitude,omitempty"`
+	Day       string   `json:"day,omitempty" toml:"day,omitempty"`
+	Night     string   `json:"night,omitempty" toml:"night,omitempty"`
+	Sunrise   string   `json:"sunrise,omitempty" toml:"sunrise,omitempty"`
+	Sunset    string   `json:"sunset,omitempty" toml:"sunset,omitempty"`
+}
+
+// DateRange selects a palette between two dates, inclusive. Dates are either
+// MM-DD to repeat every year, or YYYY-MM-DD. To defaults to From.
+type DateRange struct {
+	Palette string `json:"palette" toml:"palette"`
+	From    string `json:"from" toml:"from"`
+	To      string `json:"to,omitempty" toml:"to,omitempty"`
+}
+
+// Event returns the palette of the first date range that includes now
+func (p *Palettes) Event(now time.Time) string {
+	for _, dates := range p.Dates {
+		if dates.Includes(now) {
+			return dates.Palette
+		}
+	}
+
+	return ""
+}
+
+// Palette returns the day or night palette for the given time
+func (s *Schedule) Palette(now time.Time) string {
+	if s.IsDay(now) {
+		return s.Day
+	}
+
+	return s.Night
+}
+
+// IsDay reports whether the time is between sunrise and sunset. The location takes
+// precedence over the configured times, which default to 07:00 and 19:00.
+func (s *Schedule) IsDay(now time.Time) bool {
+	if s.Latitude != nil && s.Longitude != nil {
+		sunrise, sunset, polar := SunTimes(now, *s.Latitude, *s.Longitude)
+		if polar != nil {
+			return *polar
+		}
+
+		return !now.Before(sunrise) && now.Before(sunset)
+	}
+
+	sunrise, err := clockTime(now, s.Sunrise, defaultSunrise)
+	if err != nil {
+		log.Error(err)
+	}
+
+	sunset, err := clockTime(now, s.Sunset, defaultSunset)
+	if err != nil {
+		log.Error(err)
+	}
+
+	// a night shift schedule, like a sunrise at 20:00
+	if sunset.Before(sunrise) {
+		return !now.Before(sunrise) || now.Before(sunset)
+	}
+
+	return !now.Before(sunrise) && now.Before(sunset)
+}
+
+func clockTime(now time.Time, value, fallback string) (time.Time, error) {
+	var err error
+
+	if len(value) != 0 {
+		var clock time.Time
+		if clock, err = time.Parse(dayTimeLayout, value); err == nil {
+			return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+		}
+
+		err = fmt.Errorf("palettes: invalid time %s, expected HH:MM", value)
+	}
+
+	clock, _ := time.Parse(dayTimeLayout, fallback)
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), err
+}
+
+// Includes reports whether the date of the given time is part of the range.
+// Yearly ranges can wrap around new year, like 12-31 to 01-01.
+func (d *DateRange) Includes(now time.Time) bool {
+	to := d.To
+	if len(to) == 0 {
+		to = d.From
+	}
+
+	from, fromYearly, err := parseDate(d.From)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	until, untilYearly, err := parseDate(to)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	if fromYearly != untilYearly {
+		log.Error(fmt.Errorf("palettes: date range %s to %s mixes yearly and absolute dates", d.From, to))
+		return false
+	}
+
+	today := now.Year()*10000 + int(now.Month())*100 + now.Day()
+
+	if !fromYearly {
+		return today >= from && today <= until
+	}
+
+	today %= 10000
+
+	if from > until {
+		return today >= from || today <= until
+	}
+
+	return today >= from && today <= until
+}
+
+// parseDate returns the date as YYYYMMDD, or MMDD for a yearly date
+func parseDate(value string) (int, bool, error) {
+	if date, err := time.Parse(dayLayout, value); err == nil {
+		return int(date.Month())*100 + date.Day(), true, nil
+	}
+
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return date.Year()*10000 + int(date.Month())*100 + date.Day(), false, nil
+	}
+
+	return 0, false, fmt.Errorf("palettes: invalid date %s, expected MM-DD or YYYY-MM-DD", value)
+}
+
+// SunTimes calculates the sunrise and sunset on the day of the given time using the sunrise equation,
+// which is accurate to a minute or two. Latitude is positive to the north, longitude to the east.
+// Near the poles the sun might not rise or set at all, polar is then set to whether it's daylight all day.
+func SunTimes(now time.Time, latitude, longitude float64) (sunrise, sunset time.Time, polar *bool) {
+	radians := func(degrees float64) float64 {
+		return degrees * math.Pi / 180
+	}
+
+	degrees := func(radians float64) float64 {
+		return radians * 180 / math.Pi
+	}
+
+	// days since the J2000 epoch, for the local date
+	epoch := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := math.Round(date.Sub(epoch).Hours() / 24)
+
+	meanSolarTime := days + 0.0008 - longitude/360
+	meanAnomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	center := 1.9148*math.Sin(radians(meanAnomaly)) + 0.02*math.Sin(radians(2*meanAnomaly)) + 0.0003*math.Sin(radians(3*meanAnomaly))
+	eclipticLongitude := math.Mod(meanAnomaly+center+180+102.9372, 360)
+	transit := 2451545 + meanSolarTime + 0.0053*math.Sin(radians(meanAnomaly)) - 0.0069*math.Sin(radians(2*eclipticLongitude))
+
+	declination := math.Asin(math.Sin(radians(eclipticLongitude)) * math.Sin(radians(23.4397)))
+	hourAngle := (math.Sin(radians(-0.833)) - math.Sin(radians(latitude))*math.Sin(declination)) / (math.Cos(radians(latitude)) * math.Cos(declination))
+
+	if hourAngle < -1 || hourAngle > 1 {
+		daylight := hourAngle < -1
+		return time.Time{}, time.Time{}, &daylight
+	}
+
+	offset := degrees(math.Acos(hourAngle)) / 360
+
+	julianToTime := func(julian float64) time.Time {
+		seconds := (julian - 2440587.5) * 86400
+		return time.Unix(int64(math.Round(seconds)), 0).In(now.Location())
+	}
+
+	return julianToTime(transit - offset), julianToTime(transit + offset), nil
+}
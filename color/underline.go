@@ -0,0 +1,58 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UnderlineColor converts a color to the SGR 58 sequence parameters for the current profile.
+// There's no 16 color variant, named ANSI colors use their 256 color index so
+// they follow the terminal's theme.
+func UnderlineColor(value Ansi, resolve func(Ansi) (Ansi, error)) (string, error) {
+	if CurrentProfile == NoColor {
+		return "", nil
+	}
+
+	value = Ansi(strings.TrimSpace(value.String()))
+
+	if resolve != nil {
+		resolved, err := resolve(value)
+		if err != nil {
+			return "", err
+		}
+
+		value = resolved
+	}
+
+	if value.IsFunction() {
+		resolved, err := Evaluate(value, resolve)
+		if err != nil {
+			return "", err
+		}
+
+		value = resolved
+	}
+
+	if index, OK := ansi16Names[value]; OK {
+		return fmt.Sprintf("58;5;%d", index), nil
+	}
+
+	if index, err := strconv.ParseUint(value.String(), 10, 8); err == nil {
+		return fmt.Sprintf("58;5;%d", index), nil
+	}
+
+	rgb, OK := hexToRGB(value.String())
+	if !OK {
+		return "", fmt.Errorf("invalid underline color: %s", value)
+	}
+
+	switch CurrentProfile {
+	case ANSI16:
+		return fmt.Sprintf("58;5;%d", nearest16(rgb)), nil
+	case ANSI256:
+		return fmt.Sprintf("58;5;%d", nearest256(rgb)), nil
+	default:
+		return fmt.Sprintf("58;2;%d;%d;%d", rgb.R, rgb.G, rgb.B), nil
+	}
+}
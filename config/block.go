@@ -1,5 +1,12 @@
 package config
 
+import (
+	"strings"
+
+	"github.com/LNKLEO/OMP/log"
+	"github.com/LNKLEO/OMP/template"
+)
+
 // BlockType type of block
 type BlockType string
 
@@ -9,6 +16,9 @@ type BlockAlignment string
 // Overflow defines how to handle a right block that overflows with the previous block
 type Overflow string
 
+// Layout defines how blocks are laid out when they don't fit the terminal width
+type Layout string
+
 const (
 	// Prompt writes one or more Segments
 	Prompt BlockType = "prompt"
@@ -16,12 +26,17 @@ const (
 	RPrompt BlockType = "rprompt"
 	// Left aligns left
 	Left BlockAlignment = "left"
+	// Center aligns in the middle, between left and right aligned blocks on the same line
+	Center BlockAlignment = "center"
 	// Right aligns right
 	Right BlockAlignment = "right"
 	// Break adds a line break
 	Break Overflow = "break"
 	// Hide hides the block
 	Hide Overflow = "hide"
+	// Reflow measures all blocks on a line and moves the lowest priority segments
+	// to lines of their own when they don't fit the terminal width
+	Reflow Layout = "reflow"
 )
 
 // Block defines a part of the prompt with optional segments
@@ -32,9 +47,32 @@ type Block struct {
 	Overflow        Overflow       `json:"overflow,omitempty" toml:"overflow,omitempty"`
 	LeadingDiamond  string         `json:"leading_diamond,omitempty" toml:"leading_diamond,omitempty"`
 	TrailingDiamond string         `json:"trailing_diamond,omitempty" toml:"trailing_diamond,omitempty"`
+	If              string         `json:"if,omitempty" toml:"if,omitempty"`
 	Segments        []*Segment     `json:"segments,omitempty" toml:"segments,omitempty"`
 	MaxWidth        int            `json:"max_width,omitempty" toml:"max_width,omitempty"`
 	MinWidth        int            `json:"min_width,omitempty" toml:"min_width,omitempty"`
 	Newline         bool           `json:"newline,omitempty" toml:"newline,omitempty"`
 	Force           bool           `json:"force,omitempty" toml:"force,omitempty"`
 }
+
+// Enabled renders the block's if template, after its segments executed, to decide whether the block can be written.
+// The block is written when there's no template, or when it renders to something other than empty or false.
+func (b *Block) Enabled() bool {
+	if len(b.If) == 0 {
+		return true
+	}
+
+	tmpl := &template.Text{
+		Template: b.If,
+	}
+
+	text, err := tmpl.Render()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	text = strings.TrimSpace(text)
+
+	return len(text) != 0 && text != "false"
+}
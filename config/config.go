@@ -1,11 +1,14 @@
 package config
 
 import (
+	"time"
+
 	"github.com/LNKLEO/OMP/color"
 	"github.com/LNKLEO/OMP/runtime"
 	"github.com/LNKLEO/OMP/segments"
 	"github.com/LNKLEO/OMP/shell"
 	"github.com/LNKLEO/OMP/template"
+	"github.com/LNKLEO/OMP/terminal"
 )
 
 const (
@@ -18,26 +21,32 @@ const (
 
 // Config holds all the theme for rendering the prompt
 type Config struct {
-	Version                 int             `json:"version" toml:"version"`
-	FinalSpace              bool            `json:"final_space,omitempty" toml:"final_space,omitempty"`
-	ConsoleTitleTemplate    string          `json:"console_title_template,omitempty" toml:"console_title_template,omitempty"`
-	TerminalBackground      color.Ansi      `json:"terminal_background,omitempty" toml:"terminal_background,omitempty"`
-	AccentColor             color.Ansi      `json:"accent_color,omitempty" toml:"accent_color,omitempty"`
-	Blocks                  []*Block        `json:"blocks,omitempty" toml:"blocks,omitempty"`
-	Tooltips                []*Segment      `json:"tooltips,omitempty" toml:"tooltips,omitempty"`
-	TransientPrompt         *Segment        `json:"transient_prompt,omitempty" toml:"transient_prompt,omitempty"`
-	ValidLine               *Segment        `json:"valid_line,omitempty" toml:"valid_line,omitempty"`
-	ErrorLine               *Segment        `json:"error_line,omitempty" toml:"error_line,omitempty"`
-	SecondaryPrompt         *Segment        `json:"secondary_prompt,omitempty" toml:"secondary_prompt,omitempty"`
-	DebugPrompt             *Segment        `json:"debug_prompt,omitempty" toml:"debug_prompt,omitempty"`
-	Palette                 color.Palette   `json:"palette,omitempty" toml:"palette,omitempty"`
-	Palettes                *color.Palettes `json:"palettes,omitempty" toml:"palettes,omitempty"`
-	Cycle                   color.Cycle     `json:"cycle,omitempty" toml:"cycle,omitempty"`
-	ShellIntegration        bool            `json:"shell_integration,omitempty" toml:"shell_integration,omitempty"`
-	PWD                     string          `json:"pwd,omitempty" toml:"pwd,omitempty"`
-	Var                     map[string]any  `json:"var,omitempty" toml:"var,omitempty"`
-	EnableCursorPositioning bool            `json:"enable_cursor_positioning,omitempty" toml:"enable_cursor_positioning,omitempty"`
-	PatchPwshBleed          bool            `json:"patch_pwsh_bleed,omitempty" toml:"patch_pwsh_bleed,omitempty"`
+	Version                 int               `json:"version" toml:"version"`
+	FinalSpace              bool              `json:"final_space,omitempty" toml:"final_space,omitempty"`
+	ConsoleTitleTemplate    string            `json:"console_title_template,omitempty" toml:"console_title_template,omitempty"`
+	TerminalBackground      color.Ansi        `json:"terminal_background,omitempty" toml:"terminal_background,omitempty"`
+	AccentColor             color.Ansi        `json:"accent_color,omitempty" toml:"accent_color,omitempty"`
+	Blocks                  []*Block          `json:"blocks,omitempty" toml:"blocks,omitempty"`
+	Tooltips                []*Segment        `json:"tooltips,omitempty" toml:"tooltips,omitempty"`
+	TransientPrompt         *Segment          `json:"transient_prompt,omitempty" toml:"transient_prompt,omitempty"`
+	ValidLine               *Segment          `json:"valid_line,omitempty" toml:"valid_line,omitempty"`
+	ErrorLine               *Segment          `json:"error_line,omitempty" toml:"error_line,omitempty"`
+	SecondaryPrompt         *Segment          `json:"secondary_prompt,omitempty" toml:"secondary_prompt,omitempty"`
+	DebugPrompt             *Segment          `json:"debug_prompt,omitempty" toml:"debug_prompt,omitempty"`
+	Palette                 color.Palette     `json:"palette,omitempty" toml:"palette,omitempty"`
+	Palettes                *color.Palettes   `json:"palettes,omitempty" toml:"palettes,omitempty"`
+	PaletteImport           string            `json:"palette_import,omitempty" toml:"palette_import,omitempty"`
+	Cycle                   color.Cycle       `json:"cycle,omitempty" toml:"cycle,omitempty"`
+	ShellIntegration        bool              `json:"shell_integration,omitempty" toml:"shell_integration,omitempty"`
+	PWD                     string            `json:"pwd,omitempty" toml:"pwd,omitempty"`
+	Var                     map[string]any    `json:"var,omitempty" toml:"var,omitempty"`
+	EnableCursorPositioning bool              `json:"enable_cursor_positioning,omitempty" toml:"enable_cursor_positioning,omitempty"`
+	PatchPwshBleed          bool              `json:"patch_pwsh_bleed,omitempty" toml:"patch_pwsh_bleed,omitempty"`
+	Layout                  Layout            `json:"layout,omitempty" toml:"layout,omitempty"`
+	ColorProfile            color.Profile     `json:"color_profile,omitempty" toml:"color_profile,omitempty"`
+	Contrast                *color.Contrast   `json:"contrast,omitempty" toml:"contrast,omitempty"`
+	Glyphs                  terminal.GlyphSet `json:"glyphs,omitempty" toml:"glyphs,omitempty"`
+	QueryTerminalBackground *bool             `json:"query_terminal_background,omitempty" toml:"query_terminal_background,omitempty"`
 
 	// Deprecated
 	OSC99 bool `json:"osc99,omitempty" toml:"osc99,omitempty"`
@@ -51,23 +60,75 @@ type Config struct {
 	env     runtime.Environment
 }
 
+// CanQueryTerminalBackground tells whether the terminal can be queried for its background color,
+// which is the case unless disabled in the config.
+func (cfg *Config) CanQueryTerminalBackground() bool {
+	return cfg.QueryTerminalBackground == nil || *cfg.QueryTerminalBackground
+}
+
+// ConfigureTerminal sets up the color profile, glyphs and colors of the terminal writer. The
+// color profile given takes precedence over the config, template.Init needs to run first.
+func (cfg *Config) ConfigureTerminal(env runtime.Environment, colorProfile string) {
+	color.OverrideProfile(colorProfile, string(cfg.ColorProfile))
+	terminal.OverrideGlyphs(env.Getenv("OMP_GLYPHS"), string(cfg.Glyphs))
+
+	terminal.BackgroundColor = cfg.TerminalBackground.ResolveTemplate()
+
+	// blending and contrast checks fall back to the detected background
+	color.TerminalBackground = terminal.BackgroundColor
+	if len(color.TerminalBackground) == 0 {
+		color.TerminalBackground = color.Ansi(template.Cache.TerminalBackground)
+	}
+
+	terminal.Colors = cfg.MakeColors(env)
+	terminal.Contrast = cfg.Contrast
+}
+
 func (cfg *Config) MakeColors(env runtime.Environment) color.String {
 	cacheDisabled := env.Getenv("OMP_CACHE_DISABLED") == "1"
-	return color.MakeColors(cfg.getPalette(), !cacheDisabled, cfg.AccentColor, env)
+	return color.MakeColors(cfg.getPalette(env), !cacheDisabled, cfg.AccentColor, env)
 }
 
-func (cfg *Config) getPalette() color.Palette {
+func (cfg *Config) getPalette(env runtime.Environment) color.Palette {
+	return cfg.importPalette(env, cfg.selectPalette())
+}
+
+// selectPalette picks a palette from the list, in order of precedence: the first date range
+// including today, the template and the day/night schedule. The name is available as .PaletteName.
+func (cfg *Config) selectPalette() color.Palette {
 	if cfg.Palettes == nil {
 		return cfg.Palette
 	}
-	tmpl := &template.Text{
-		Template: cfg.Palettes.Template,
+
+	now := time.Now()
+
+	candidates := []func() string{
+		func() string {
+			return cfg.Palettes.Event(now)
+		},
+		func() string {
+			tmpl := &template.Text{
+				Template: cfg.Palettes.Template,
+			}
+			palette, _ := tmpl.Render()
+			return palette
+		},
+		func() string {
+			if cfg.Palettes.Schedule == nil {
+				return ""
+			}
+			return cfg.Palettes.Schedule.Palette(now)
+		},
 	}
-	if palette, err := tmpl.Render(); err == nil {
-		if p, ok := cfg.Palettes.List[palette]; ok {
+
+	for _, candidate := range candidates {
+		name := candidate()
+		if p, ok := cfg.Palettes.List[name]; ok {
+			template.Cache.PaletteName = name
 			return p
 		}
 	}
+
 	return cfg.Palette
 }
 
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/LNKLEO/OMP/color"
+)
+
+// ColorContrast is the contrast ratio of a segment's foreground on its background
+type ColorContrast struct {
+	Segment    string
+	Foreground color.Ansi
+	Background color.Ansi
+	Ratio      float64
+}
+
+// ColorContrasts lists the contrast ratio of the foreground and background of every segment.
+// Only the static colors are validated, colors coming from templates or keywords
+// depend on the context and are checked at runtime when contrast checking is enabled.
+// Transparent backgrounds are compared to the terminal background set by ConfigureTerminal.
+func (cfg *Config) ColorContrasts(resolve func(color.Ansi) (color.Ansi, error)) []*ColorContrast {
+	var contrasts []*ColorContrast
+
+	var add func(name string, segment *Segment, parent color.Ansi)
+	add = func(name string, segment *Segment, parent color.Ansi) {
+		if segment == nil {
+			return
+		}
+
+		background := segment.Background
+		if background.IsClear() {
+			background = parent
+		}
+
+		ratio, err := color.Ratio(segment.Foreground, background, resolve)
+		if err == nil {
+			contrasts = append(contrasts, &ColorContrast{
+				Segment:    name,
+				Foreground: segment.Foreground,
+				Background: background,
+				Ratio:      ratio,
+			})
+		}
+
+		for _, child := range segment.Segments {
+			add(fmt.Sprintf("%s > %s", name, child.Name()), child, background)
+		}
+	}
+
+	for i, block := range cfg.Blocks {
+		for _, segment := range block.Segments {
+			add(fmt.Sprintf("block %d > %s", i+1, segment.Name()), segment, color.TerminalBackground)
+		}
+	}
+
+	for _, tooltip := range cfg.Tooltips {
+		add(fmt.Sprintf("tooltip > %s", tooltip.Name()), tooltip, color.TerminalBackground)
+	}
+
+	add("transient_prompt", cfg.TransientPrompt, color.TerminalBackground)
+	add("secondary_prompt", cfg.SecondaryPrompt, color.TerminalBackground)
+	add("debug_prompt", cfg.DebugPrompt, color.TerminalBackground)
+	add("valid_line", cfg.ValidLine, color.TerminalBackground)
+	add("error_line", cfg.ErrorLine, color.TerminalBackground)
+
+	return contrasts
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LNKLEO/OMP/cache"
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/log"
+	"github.com/LNKLEO/OMP/runtime"
+	"github.com/LNKLEO/OMP/runtime/path"
+
+	json "github.com/goccy/go-json"
+	yaml "github.com/goccy/go-yaml"
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// importPalette adds the colors of the terminal theme set in palette_import,
+// the colors defined in the config take precedence over the imported ones
+func (cfg *Config) importPalette(env runtime.Environment, palette color.Palette) color.Palette {
+	if len(cfg.PaletteImport) == 0 {
+		return palette
+	}
+
+	themePath := path.ReplaceTildePrefixWithHomeDir(cfg.PaletteImport)
+	if !filepath.IsAbs(themePath) && len(cfg.origin) != 0 {
+		themePath = filepath.Join(filepath.Dir(cfg.origin), themePath)
+	}
+
+	imported, err := importThemePalette(env, themePath)
+	if err != nil {
+		log.Error(err)
+		return palette
+	}
+
+	for key, value := range palette {
+		imported[key] = value
+	}
+
+	return imported
+}
+
+// importThemePalette parses the terminal theme once per session, or when it changes
+func importThemePalette(env runtime.Environment, themePath string) (color.Palette, error) {
+	info, err := os.Stat(themePath)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("palette_import_%s_%d", themePath, info.ModTime().UnixNano())
+
+	if data, OK := env.Session().Get(key); OK {
+		var imported color.Palette
+		if err := json.Unmarshal([]byte(data), &imported); err == nil {
+			return imported, nil
+		}
+	}
+
+	imported, err := color.ImportPalette(themePath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(imported); err == nil {
+		env.Session().Set(key, string(data), cache.ONEDAY)
+	}
+
+	return imported, nil
+}
+
+// ImportPalette overwrites the palette with the colors of the terminal theme
+func (cfg *Config) ImportPalette(themePath string, format color.ThemeFormat) error {
+	imported, err := color.ImportPalette(themePath, format)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Palette == nil {
+		cfg.Palette = make(color.Palette)
+	}
+
+	for key, value := range imported {
+		cfg.Palette[key] = value
+	}
+
+	return nil
+}
+
+// ExportPalette formats the palette as a config snippet
+func ExportPalette(palette color.Palette, format string) string {
+	snippet := struct {
+		Palette color.Palette `json:"palette" toml:"palette"`
+	}{
+		Palette: palette,
+	}
+
+	var result bytes.Buffer
+
+	switch format {
+	case YAML:
+		if err := yaml.NewEncoder(&result).Encode(snippet); err != nil {
+			return ""
+		}
+	case JSON:
+		jsonEncoder := json.NewEncoder(&result)
+		jsonEncoder.SetIndent("", "  ")
+		if err := jsonEncoder.Encode(snippet); err != nil {
+			return ""
+		}
+	case TOML:
+		tomlEncoder := toml.NewEncoder(&result)
+		tomlEncoder.SetIndentTables(true)
+		if err := tomlEncoder.Encode(snippet); err != nil {
+			return ""
+		}
+	}
+
+	return strings.TrimSpace(result.String()) + "\n"
+}
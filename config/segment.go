@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/LNKLEO/OMP/cache"
@@ -63,16 +64,19 @@ type Segment struct {
 	Templates              template.List  `json:"templates,omitempty" toml:"templates,omitempty"`
 	ExcludeFolders         []string       `json:"exclude_folders,omitempty" toml:"exclude_folders,omitempty"`
 	IncludeFolders         []string       `json:"include_folders,omitempty" toml:"include_folders,omitempty"`
+	Segments               []*Segment     `json:"segments,omitempty" toml:"segments,omitempty"`
 	Needs                  []string       `json:"-" toml:"-"`
 	NameLength             int            `json:"-" toml:"-"`
 	MaxWidth               int            `json:"max_width,omitempty" toml:"max_width,omitempty"`
 	MinWidth               int            `json:"min_width,omitempty" toml:"min_width,omitempty"`
+	Priority               int            `json:"priority,omitempty" toml:"priority,omitempty"`
 	Duration               time.Duration  `json:"-" toml:"-"`
 	Interactive            bool           `json:"interactive,omitempty" toml:"interactive,omitempty"`
 	Enabled                bool           `json:"-" toml:"-"`
 	Newline                bool           `json:"newline,omitempty" toml:"newline,omitempty"`
 	InvertPowerline        bool           `json:"invert_powerline,omitempty" toml:"invert_powerline,omitempty"`
 	restored               bool           `json:"-" toml:"-"`
+	grouped                bool           `json:"-" toml:"-"`
 }
 
 func (segment *Segment) Name() string {
@@ -113,6 +117,10 @@ func (segment *Segment) Execute(env runtime.Environment) {
 		return
 	}
 
+	if segment.mock() {
+		return
+	}
+
 	if segment.restoreCache() {
 		return
 	}
@@ -121,10 +129,117 @@ func (segment *Segment) Execute(env runtime.Environment) {
 		return
 	}
 
+	if group, OK := segment.writer.(SegmentGroup); OK {
+		if err := segment.validateChildren(); err != nil {
+			log.Error(err)
+			return
+		}
+
+		segment.executeChildren(group)
+	}
+
 	if segment.writer.Enabled() {
 		segment.Enabled = true
-		template.Cache.AddSegmentData(segment.Name(), segment.writer)
+		segment.addSegmentData()
+	}
+}
+
+// addSegmentData exposes the segment's data to the templates of the other segments. The children of
+// a group are only available through the group's .Children, so they can't overwrite a top level segment.
+func (segment *Segment) addSegmentData() {
+	if segment.grouped {
+		return
+	}
+
+	template.Cache.AddSegmentData(segment.Name(), segment.writer)
+}
+
+func (segment *Segment) removeSegmentData() {
+	if segment.grouped {
+		return
+	}
+
+	template.Cache.RemoveSegmentData(segment.Name())
+}
+
+// validateChildren makes sure the child segments of a group have unique names,
+// as these are used to access their data in the group's template.
+func (segment *Segment) validateChildren() error {
+	names := make(map[string]bool, len(segment.Segments))
+
+	for _, child := range segment.Segments {
+		name := child.Name()
+		if names[name] {
+			return fmt.Errorf("duplicate child segment %s in group %s, set a unique alias", name, segment.Name())
+		}
+
+		names[name] = true
+	}
+
+	return nil
+}
+
+// executeChildren executes and renders the child segments of a group,
+// the enabled ones are handed to the group writer in their configured order.
+func (segment *Segment) executeChildren(group SegmentGroup) {
+	var wg sync.WaitGroup
+
+	for _, child := range segment.Segments {
+		wg.Add(1)
+
+		child.grouped = true
+
+		go func(child *Segment) {
+			defer wg.Done()
+			child.Execute(segment.env)
+		}(child)
+	}
+
+	wg.Wait()
+
+	for _, child := range segment.Segments {
+		child.Render()
+
+		if !child.Enabled {
+			continue
+		}
+
+		text := child.Text()
+
+		// the children share the group's background, but can have their own foreground
+		if foreground := child.ResolveForeground(); len(foreground) != 0 {
+			text = fmt.Sprintf("<%s>%s</>", foreground, text)
+		}
+
+		group.AddChild(child.Name(), child.writer, text)
+	}
+}
+
+// mock uses the data set for the segment's alias or type in the mocks flag, instead of executing it
+func (segment *Segment) mock() bool {
+	mocks := segment.env.Flags().Mocks
+	if len(mocks) == 0 {
+		return false
+	}
+
+	data, OK := mocks[segment.Alias]
+	if !OK {
+		data, OK = mocks[string(segment.Type)]
+	}
+
+	if !OK {
+		return false
+	}
+
+	if err := json.Unmarshal([]byte(data), segment.writer); err != nil {
+		log.Error(err)
+		return false
 	}
+
+	segment.Enabled = true
+	segment.addSegmentData()
+
+	return true
 }
 
 func (segment *Segment) Render() {
@@ -136,7 +251,7 @@ func (segment *Segment) Render() {
 	segment.Enabled = len(strings.ReplaceAll(text, " ", "")) > 0
 
 	if !segment.Enabled {
-		template.Cache.RemoveSegmentData(segment.Name())
+		segment.removeSegmentData()
 		return
 	}
 
@@ -144,7 +259,23 @@ func (segment *Segment) Render() {
 	segment.setCache()
 
 	// We do this to make `.Text` available for a cross-segment reference in an extra prompt.
-	template.Cache.AddSegmentData(segment.Name(), segment.writer)
+	segment.addSegmentData()
+}
+
+// Fit asks the writer to shorten its output by the overflowing width and
+// renders the segment again when it did.
+func (segment *Segment) Fit(overflow int) bool {
+	if !segment.Enabled {
+		return false
+	}
+
+	fitter, OK := segment.writer.(SegmentFitter)
+	if !OK || !fitter.Fit(overflow) {
+		return false
+	}
+
+	segment.Render()
+	return true
 }
 
 func (segment *Segment) Text() string {
@@ -236,7 +367,7 @@ func (segment *Segment) restoreCache() bool {
 	}
 
 	segment.Enabled = true
-	template.Cache.AddSegmentData(segment.Name(), segment.writer)
+	segment.addSegmentData()
 
 	log.Debug("restored segment from cache: ", segment.Name())
 
@@ -20,6 +20,18 @@ type SegmentWriter interface {
 	Init(props properties.Properties, env runtime.Environment)
 }
 
+// SegmentGroup is implemented by segment writers composed of child segments,
+// the children are executed and rendered before the writer is asked whether it's enabled.
+type SegmentGroup interface {
+	AddChild(name string, writer any, text string)
+}
+
+// SegmentFitter is implemented by segment writers that can shorten their output
+// when the line they're on overflows the terminal width.
+type SegmentFitter interface {
+	Fit(overflow int) bool
+}
+
 const (
 	// Plain writes it without ornaments
 	Plain SegmentStyle = "plain"
@@ -29,6 +41,8 @@ const (
 	Accordion SegmentStyle = "accordion"
 	// Diamond writes the prompt shaped with a leading and trailing symbol
 	Diamond SegmentStyle = "diamond"
+	// AWS writes the active aws context
+	AWS SegmentType = "aws"
 	// AZ writes the Azure subscription info we're currently in
 	AZ SegmentType = "az"
 	// AZD writes the Azure Developer CLI environment info we're current in
@@ -37,28 +51,42 @@ const (
 	AZFUNC SegmentType = "azfunc"
 	// BATTERY writes the battery percentage
 	BATTERY SegmentType = "battery"
+	// BUN writes the active bun version
+	BUN SegmentType = "bun"
 	// Buf segment writes the active buf version
 	CMAKE SegmentType = "cmake"
 	// CMD writes the output of a shell command
 	CMD SegmentType = "command"
 	// CONNECTION writes a connection's information
 	CONNECTION SegmentType = "connection"
+	// DENO writes the active deno version
+	DENO SegmentType = "deno"
 	// CRYSTAL writes the active crystal version
 	DOCKER SegmentType = "docker"
 	// DOTNET writes which dotnet version is currently active
 	DOTNET SegmentType = "dotnet"
+	// ENVIRONMENT writes the container or development environment we're in
+	ENVIRONMENT SegmentType = "environment"
 	// ELIXIR writes the elixir version
 	EXECUTIONTIME SegmentType = "executiontime"
 	// EXIT writes the last exit code
 	EXIT SegmentType = "exit"
+	// GCP writes the active GCP context
+	GCP SegmentType = "gcp"
 	// GIT represents the git status and information
 	GIT SegmentType = "git"
 	// GITVERSION represents the gitversion information
 	GOLANG SegmentType = "go"
+	// GROUP writes its child segments as one
+	GROUP SegmentType = "group"
 	// HASKELL segment
 	HASKELL SegmentType = "haskell"
+	// HELM writes the Helm chart we're currently in
+	HELM SegmentType = "helm"
 	// IPIFY segment
 	IPIFY SegmentType = "ipify"
+	// KUBECTL writes the Kubernetes context we're currently in
+	KUBECTL SegmentType = "kubectl"
 	// NETWORKS get all current active network connections
 	NETWORKS SegmentType = "networks"
 	// NODE writes which node version is currently active
@@ -87,6 +115,8 @@ const (
 	STATUS SegmentType = "status"
 	// SYSTEMINFO writes system information (memory, cpu, load)
 	SYSTEMINFO SegmentType = "sysinfo"
+	// TERRAFORM writes the terraform or OpenTofu workspace we're currently in
+	TERRAFORM SegmentType = "terraform"
 	// TEXT writes a text
 	TEXT SegmentType = "text"
 	// TIME writes the current timestamp
@@ -100,21 +130,29 @@ const (
 // Segments contains all available prompt segment writers.
 // Consumers of the library can also add their own segment writer.
 var Segments = map[SegmentType]func() SegmentWriter{
+	AWS:             func() SegmentWriter { return &segments.Aws{} },
 	AZ:              func() SegmentWriter { return &segments.Az{} },
 	AZD:             func() SegmentWriter { return &segments.Azd{} },
 	AZFUNC:          func() SegmentWriter { return &segments.AzFunc{} },
 	BATTERY:         func() SegmentWriter { return &segments.Battery{} },
+	BUN:             func() SegmentWriter { return &segments.Bun{} },
 	CMAKE:           func() SegmentWriter { return &segments.Cmake{} },
 	CMD:             func() SegmentWriter { return &segments.Cmd{} },
 	CONNECTION:      func() SegmentWriter { return &segments.Connection{} },
+	DENO:            func() SegmentWriter { return &segments.Deno{} },
 	DOCKER:          func() SegmentWriter { return &segments.Docker{} },
 	DOTNET:          func() SegmentWriter { return &segments.Dotnet{} },
+	ENVIRONMENT:     func() SegmentWriter { return &segments.Environment{} },
 	EXECUTIONTIME:   func() SegmentWriter { return &segments.Executiontime{} },
 	EXIT:            func() SegmentWriter { return &segments.Status{} },
+	GCP:             func() SegmentWriter { return &segments.Gcp{} },
 	GIT:             func() SegmentWriter { return &segments.Git{} },
 	GOLANG:          func() SegmentWriter { return &segments.Golang{} },
+	GROUP:           func() SegmentWriter { return &segments.Group{} },
 	HASKELL:         func() SegmentWriter { return &segments.Haskell{} },
+	HELM:            func() SegmentWriter { return &segments.Helm{} },
 	IPIFY:           func() SegmentWriter { return &segments.IPify{} },
+	KUBECTL:         func() SegmentWriter { return &segments.Kubectl{} },
 	NETWORKS:        func() SegmentWriter { return &segments.Networks{} },
 	NODE:            func() SegmentWriter { return &segments.Node{} },
 	NPM:             func() SegmentWriter { return &segments.Npm{} },
@@ -129,6 +167,7 @@ var Segments = map[SegmentType]func() SegmentWriter{
 	SHELL:           func() SegmentWriter { return &segments.Shell{} },
 	STATUS:          func() SegmentWriter { return &segments.Status{} },
 	SYSTEMINFO:      func() SegmentWriter { return &segments.SystemInfo{} },
+	TERRAFORM:       func() SegmentWriter { return &segments.Terraform{} },
 	TEXT:            func() SegmentWriter { return &segments.Text{} },
 	TIME:            func() SegmentWriter { return &segments.Time{} },
 	WINREG:          func() SegmentWriter { return &segments.WindowsRegistry{} },
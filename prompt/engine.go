@@ -19,6 +19,7 @@ type Engine struct {
 	Config                *config.Config
 	activeSegment         *config.Segment
 	previousActiveSegment *config.Segment
+	prerendered           map[*config.Block]*prerenderedBlock
 	rprompt               string
 	Overflow              config.Overflow
 	prompt                strings.Builder
@@ -26,6 +27,7 @@ type Engine struct {
 	rpromptLength         int
 	Padding               int
 	Plain                 bool
+	primaryRPrompt        bool
 }
 
 const (
@@ -37,6 +39,10 @@ const (
 	TOOLTIP   = "tooltip"
 	VALID     = "valid"
 	ERROR     = "error"
+
+	// the minimal space to keep between the left and right side of the prompt
+	breathingRoom        = 5
+	rpromptBreathingRoom = 30
 )
 
 func (e *Engine) write(text string) {
@@ -69,9 +75,9 @@ func (e *Engine) canWriteRightBlock(length int, rprompt bool) (int, bool) {
 
 	availableSpace -= length
 
-	promptBreathingRoom := 5
+	promptBreathingRoom := breathingRoom
 	if rprompt {
-		promptBreathingRoom = 30
+		promptBreathingRoom = rpromptBreathingRoom
 	}
 
 	canWrite := availableSpace >= promptBreathingRoom
@@ -179,7 +185,25 @@ func (e *Engine) getTitleTemplateText() string {
 }
 
 func (e *Engine) renderBlock(block *config.Block, cancelNewline bool) bool {
-	text, length := e.writeBlockSegments(block)
+	// keep track of the color cycle so we can write the block again when needed
+	blockCycle := cycle
+
+	var text string
+	var length int
+
+	// the block might already be written when looking ahead on the same line
+	if pre, OK := e.prerendered[block]; OK {
+		delete(e.prerendered, block)
+		blockCycle, text, length = pre.cycle, pre.text, pre.length
+	} else {
+		text, length = e.writeBlockSegments(block)
+	}
+
+	if !block.Enabled() {
+		// the next block starts with the colors this one would have used
+		cycle = blockCycle
+		return false
+	}
 
 	// do not print anything when we don't have any text unless forced
 	if !block.Force && length == 0 {
@@ -197,12 +221,25 @@ func (e *Engine) renderBlock(block *config.Block, cancelNewline bool) bool {
 
 	switch block.Type {
 	case config.Prompt:
+		text, length = e.fitBlock(block, text, length, blockCycle)
+
+		// the priority decides what wraps first, rather than what's dropped
+		if e.Config.Layout == config.Reflow {
+			text, length = e.reflowBlock(block, text, length, blockCycle)
+		} else {
+			text, length = e.dropSegments(block, text, length, blockCycle)
+		}
+
 		if block.Alignment == config.Left {
 			e.currentLineLength += length
 			e.write(text)
 			return true
 		}
 
+		if block.Alignment == config.Center {
+			return e.writeCenterBlock(block, text, length)
+		}
+
 		if block.Alignment != config.Right {
 			return false
 		}
@@ -254,6 +291,118 @@ func (e *Engine) renderBlock(block *config.Block, cancelNewline bool) bool {
 	return true
 }
 
+// writeCenterBlock places a block in the middle of the line, leaving room for the right aligned
+// block that follows on the same line. When the center of the line is taken, the block moves
+// to the right of what's already written, or to the left when that makes the right block fit.
+func (e *Engine) writeCenterBlock(block *config.Block, text string, length int) bool {
+	consoleWidth, err := e.Env.TerminalWidth()
+	if err != nil || consoleWidth == 0 {
+		e.currentLineLength += length
+		e.write(text)
+		return true
+	}
+
+	// the right block needs its own breathing room after this block
+	reserved := length
+	if rightLength := e.rightBlockLength(block); rightLength > 0 {
+		reserved += rightLength + breathingRoom
+	}
+
+	space, OK := e.canWriteRightBlock(reserved, false)
+
+	if !OK {
+		e.Overflow = block.Overflow
+		defer func() {
+			e.Overflow = ""
+		}()
+
+		switch block.Overflow {
+		case config.Break:
+			e.writeNewline()
+			space, _ = e.canWriteRightBlock(reserved, false)
+		case config.Hide:
+			return true
+		default:
+			// write the block where it fits best, the right block will overflow
+			if space, OK = e.canWriteRightBlock(length, false); !OK {
+				space = 0
+			}
+		}
+	}
+
+	lineLength := e.currentLineLength % consoleWidth
+
+	padding := (consoleWidth-length)/2 - lineLength
+
+	minPadding := 0
+	if lineLength > 0 {
+		minPadding = breathingRoom
+	}
+
+	padding = min(max(padding, minPadding), space)
+	if padding < 0 {
+		padding = 0
+	}
+
+	e.currentLineLength += padding + length
+
+	if padText, OK := e.shouldFill(block.Filler, padding); OK {
+		e.write(padText)
+		e.write(text)
+		return true
+	}
+
+	e.write(strings.Repeat(" ", padding))
+	e.write(text)
+
+	return true
+}
+
+// rightBlockLength returns the width of the right aligned block following the given block on the same line.
+func (e *Engine) rightBlockLength(block *config.Block) int {
+	blocks, _ := e.lineBlocks(block)
+
+	for _, next := range blocks {
+		if next.Alignment != config.Right {
+			continue
+		}
+
+		return e.prerender(next).length
+	}
+
+	return 0
+}
+
+// fitBlock asks the segments of a block to shorten their output, one by one,
+// as long as the block doesn't fit in the available width on the current line.
+func (e *Engine) fitBlock(block *config.Block, text string, length int, blockCycle *color.Cycle) (string, int) {
+	consoleWidth, err := e.Env.TerminalWidth()
+	if err != nil || consoleWidth == 0 {
+		return text, length
+	}
+
+	available := consoleWidth - e.currentLineLength%consoleWidth
+	if block.Alignment == config.Right || block.Alignment == config.Center {
+		available -= breathingRoom
+	}
+
+	for _, segment := range block.Segments {
+		overflow := length - available
+		if overflow <= 0 {
+			break
+		}
+
+		if !segment.Fit(overflow) {
+			continue
+		}
+
+		cycle = blockCycle
+		text, length = e.rewriteBlockSegments(block)
+	}
+
+	return text, length
+}
+
 func (e *Engine) applyPowerShellBleedPatch() {
 	// when in PowerShell, we need to clear the line after the prompt
 	// to avoid the background being printed on the next line
@@ -282,7 +431,7 @@ func (e *Engine) renderActiveSegment() {
 
 	switch e.activeSegment.ResolveStyle() {
 	case config.Plain, config.Powerline:
-		terminal.Write(color.Background, color.Foreground, e.activeSegment.Text())
+		terminal.WriteText(color.Background, color.Foreground, e.activeSegment.Text())
 	case config.Diamond:
 		background := color.Transparent
 
@@ -291,10 +440,10 @@ func (e *Engine) renderActiveSegment() {
 		}
 
 		terminal.Write(background, color.Background, e.activeSegment.LeadingDiamond)
-		terminal.Write(color.Background, color.Foreground, e.activeSegment.Text())
+		terminal.WriteText(color.Background, color.Foreground, e.activeSegment.Text())
 	case config.Accordion:
 		if e.activeSegment.Enabled {
-			terminal.Write(color.Background, color.Foreground, e.activeSegment.Text())
+			terminal.WriteText(color.Background, color.Foreground, e.activeSegment.Text())
 		}
 	}
 
@@ -467,6 +616,7 @@ func (e *Engine) rectifyTerminalWidth(diff int) {
 func New(flags *runtime.Flags) *Engine {
 	flags.Config = config.Path(flags.Config)
 	cfg := config.Load(flags.Config, flags.Shell, flags.Migrate)
+	flags.QueryTerminalBackground = cfg.CanQueryTerminalBackground()
 
 	env := &runtime.Terminal{}
 	env.Init(flags)
@@ -480,8 +630,7 @@ func New(flags *runtime.Flags) *Engine {
 		cfg.ErrorLine != nil
 
 	terminal.Init(env.Shell())
-	terminal.BackgroundColor = cfg.TerminalBackground.ResolveTemplate()
-	terminal.Colors = cfg.MakeColors(env)
+	cfg.ConfigureTerminal(env, flags.ColorProfile)
 	terminal.Plain = flags.Plain
 
 	eng := &Engine{
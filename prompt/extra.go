@@ -78,7 +78,7 @@ func (e *Engine) ExtraPrompt(promptType ExtraPromptType) string {
 	foreground := color.Ansi(prompt.ForegroundTemplates.FirstMatch(nil, string(prompt.Foreground)))
 	background := color.Ansi(prompt.BackgroundTemplates.FirstMatch(nil, string(prompt.Background)))
 	terminal.SetColors(background, foreground)
-	terminal.Write(background, foreground, promptText)
+	terminal.WriteText(background, foreground, promptText)
 
 	str, length := terminal.String()
 
@@ -0,0 +1,347 @@
+package prompt
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/config"
+)
+
+type line struct {
+	text   string
+	length int
+}
+
+// reflowBlock makes the line fit the terminal width by moving segments to lines of their own, above the current line.
+// All blocks on the line are measured first, and the segments with the lowest priority are moved first, from any
+// of those blocks. Segments with the same priority move from right to left, so without priorities the trailing
+// segments wrap. The moved segments keep the alignment of their block.
+func (e *Engine) reflowBlock(block *config.Block, text string, length int, blockCycle *color.Cycle) (string, int) {
+	consoleWidth, err := e.Env.TerminalWidth()
+	if err != nil || consoleWidth == 0 {
+		return text, length
+	}
+
+	// the first block on the line already made room for the ones that follow
+	if e.currentLineLength%consoleWidth != 0 {
+		return text, length
+	}
+
+	blocks, rprompt := e.lineBlocks(block)
+	if rprompt != nil {
+		blocks = append(blocks, rprompt)
+	}
+
+	for _, next := range blocks {
+		e.prerender(next)
+	}
+
+	// restore the color cycle to where it was after looking ahead
+	current := cycle
+	defer func() {
+		cycle = current
+	}()
+
+	owners := append([]*config.Block{block}, blocks...)
+	wrapped := make(map[*config.Block][]*config.Segment)
+
+	for e.lineLength(block, length, blocks, rprompt) > consoleWidth {
+		var lowest *config.Segment
+		var owner *config.Block
+		var remaining int
+
+		for _, candidate := range owners {
+			for _, segment := range candidate.Segments {
+				if !segment.Enabled || segment.ResolveStyle() == config.Accordion {
+					continue
+				}
+
+				remaining++
+
+				if lowest == nil || segment.Priority <= lowest.Priority {
+					lowest, owner = segment, candidate
+				}
+			}
+		}
+
+		// keep at least one segment on the line
+		if remaining <= 1 {
+			break
+		}
+
+		lowest.Enabled = false
+		wrapped[owner] = append(wrapped[owner], lowest)
+
+		if owner == block {
+			cycle = blockCycle
+			text, length = e.rewriteBlockSegments(block)
+			continue
+		}
+
+		pre := e.prerendered[owner]
+		cycle = pre.cycle
+		pre.text, pre.length = e.rewriteBlockSegments(owner)
+	}
+
+	for _, owner := range owners {
+		segments := wrapped[owner]
+		if len(segments) == 0 {
+			continue
+		}
+
+		// write them in the order they're configured in
+		slices.SortFunc(segments, func(a, b *config.Segment) int {
+			return slices.Index(owner.Segments, a) - slices.Index(owner.Segments, b)
+		})
+
+		cycle = blockCycle
+		if pre, OK := e.prerendered[owner]; OK {
+			cycle = pre.cycle
+		}
+
+		for _, line := range e.wrapSegments(owner, segments, consoleWidth) {
+			switch owner.Alignment {
+			case config.Right:
+				e.write(strings.Repeat(" ", max(0, consoleWidth-line.length)))
+			case config.Center:
+				e.write(strings.Repeat(" ", max(0, consoleWidth-line.length)/2))
+			}
+
+			e.write(line.text)
+			e.writeNewline()
+		}
+	}
+
+	return text, length
+}
+
+// wrapSegments divides the segments of a block over lines of the given width, keeping them in order.
+// The segments are written as if they were the only ones in the block.
+func (e *Engine) wrapSegments(block *config.Block, segments []*config.Segment, width int) []*line {
+	// the segments were taken off the line they were written on
+	for _, segment := range segments {
+		segment.Enabled = true
+	}
+
+	defer func() {
+		for _, segment := range segments {
+			segment.Enabled = false
+		}
+	}()
+
+	var lines []*line
+	var current []*config.Segment
+	var text string
+	var length int
+
+	lineCycle := cycle
+
+	for _, segment := range segments {
+		candidate := append(slices.Clone(current), segment)
+
+		cycle = lineCycle
+		candidateText, candidateLength := e.writeSegmentList(block, candidate)
+
+		// a segment that's wider than a full line still gets a line of its own
+		if candidateLength <= width || len(current) == 0 {
+			current = candidate
+			text, length = candidateText, candidateLength
+			continue
+		}
+
+		lines = append(lines, &line{text: text, length: length})
+
+		// the previous line consumed its colors from the cycle
+		cycle = lineCycle
+		e.writeSegmentList(block, current)
+		lineCycle = cycle
+
+		current = []*config.Segment{segment}
+		text, length = e.writeSegmentList(block, current)
+	}
+
+	return append(lines, &line{text: text, length: length})
+}
+
+type prerenderedBlock struct {
+	cycle   *color.Cycle
+	text    string
+	length  int
+	enabled bool
+}
+
+// prerender writes a block ahead of time so its width is known
+// while laying out the blocks before it on the same line.
+func (e *Engine) prerender(block *config.Block) *prerenderedBlock {
+	if pre, OK := e.prerendered[block]; OK {
+		return pre
+	}
+
+	if e.prerendered == nil {
+		e.prerendered = make(map[*config.Block]*prerenderedBlock)
+	}
+
+	pre := &prerenderedBlock{cycle: cycle}
+	pre.text, pre.length = e.writeBlockSegments(block)
+	// the if template can only be rendered once the segments executed
+	pre.enabled = block.Enabled()
+	e.prerendered[block] = pre
+
+	return pre
+}
+
+// lineBlocks returns the enabled blocks that still need to be written on the same line as the given block,
+// and the right prompt when the block is on the last line of the prompt. The blocks are written ahead of time
+// to know whether they're enabled.
+func (e *Engine) lineBlocks(block *config.Block) ([]*config.Block, *config.Block) {
+	var blocks []*config.Block
+	var rprompt *config.Block
+
+	lastLine := true
+	index := slices.Index(e.Config.Blocks, block)
+
+	for _, next := range e.Config.Blocks[index+1:] {
+		if next.Type == config.RPrompt {
+			if rprompt == nil {
+				rprompt = next
+			}
+
+			continue
+		}
+
+		// a disabled block doesn't write its newline either
+		if !e.prerender(next).enabled {
+			continue
+		}
+
+		if next.Newline {
+			lastLine = false
+			break
+		}
+
+		blocks = append(blocks, next)
+	}
+
+	if rprompt == nil || !lastLine || !e.primaryRPrompt || !e.prerender(rprompt).enabled {
+		rprompt = nil
+	}
+
+	return blocks, rprompt
+}
+
+func (e *Engine) hasPriorities() bool {
+	for _, block := range e.Config.Blocks {
+		for _, segment := range block.Segments {
+			if segment.Priority > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dropSegments disables the enabled segment with the lowest priority, one at a time, as long as
+// the current line doesn't fit the terminal width. The blocks that still need to be written on
+// the same line, and the right prompt, are taken into account. Segments without a priority are never dropped.
+func (e *Engine) dropSegments(block *config.Block, text string, length int, blockCycle *color.Cycle) (string, int) {
+	if !e.hasPriorities() {
+		return text, length
+	}
+
+	consoleWidth, err := e.Env.TerminalWidth()
+	if err != nil || consoleWidth == 0 {
+		return text, length
+	}
+
+	blocks, rprompt := e.lineBlocks(block)
+	if rprompt != nil {
+		blocks = append(blocks, rprompt)
+	}
+
+	for _, next := range blocks {
+		e.prerender(next)
+	}
+
+	// restore the color cycle to where it was after looking ahead
+	current := cycle
+	defer func() {
+		cycle = current
+	}()
+
+	type candidate struct {
+		owner   *config.Block
+		segment *config.Segment
+	}
+
+	for {
+		var candidates []*candidate
+
+		for _, owner := range append([]*config.Block{block}, blocks...) {
+			for _, segment := range owner.Segments {
+				if segment.Enabled && segment.Priority > 0 {
+					candidates = append(candidates, &candidate{owner: owner, segment: segment})
+				}
+			}
+		}
+
+		if e.lineLength(block, length, blocks, rprompt) <= consoleWidth || len(candidates) == 0 {
+			return text, length
+		}
+
+		// drop the lowest priority, the rightmost one first when equal
+		lowest := candidates[0]
+		for _, c := range candidates[1:] {
+			if c.segment.Priority <= lowest.segment.Priority {
+				lowest = c
+			}
+		}
+
+		lowest.segment.Enabled = false
+
+		if lowest.owner == block {
+			cycle = blockCycle
+			text, length = e.rewriteBlockSegments(block)
+			continue
+		}
+
+		pre := e.prerendered[lowest.owner]
+		cycle = pre.cycle
+		pre.text, pre.length = e.rewriteBlockSegments(lowest.owner)
+	}
+}
+
+// lineLength returns the width of the current line once the block, of the given length,
+// and the blocks that follow it on the same line are written.
+func (e *Engine) lineLength(block *config.Block, length int, blocks []*config.Block, rprompt *config.Block) int {
+	consoleWidth, _ := e.Env.TerminalWidth()
+
+	lineLength := e.currentLineLength%consoleWidth + length
+	if block.Alignment == config.Right || block.Alignment == config.Center {
+		lineLength += breathingRoom
+	}
+
+	for _, next := range blocks {
+		pre := e.prerendered[next]
+		if pre.length == 0 {
+			continue
+		}
+
+		lineLength += pre.length
+
+		switch {
+		case next.Type == config.RPrompt:
+			lineLength += rpromptBreathingRoom
+		case next.Alignment == config.Right, next.Alignment == config.Center:
+			lineLength += breathingRoom
+		}
+	}
+
+	// the right prompt was already written before this block
+	if rprompt == nil && len(e.rprompt) != 0 && len(blocks) == 0 && e.primaryRPrompt {
+		lineLength += e.rpromptLength + rpromptBreathingRoom
+	}
+
+	return lineLength
+}
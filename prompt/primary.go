@@ -51,6 +51,7 @@ func (e *Engine) writePrimaryPrompt(needsPrimaryRPrompt bool) {
 
 	// cache a pointer to the color cycle
 	cycle = &e.Config.Cycle
+	e.primaryRPrompt = needsPrimaryRPrompt
 	var cancelNewline, didRender bool
 
 	for i, block := range e.Config.Blocks {
@@ -97,7 +98,7 @@ func (e *Engine) needsPrimaryRightPrompt() bool {
 	}
 
 	switch e.Env.Shell() {
-	case shell.PWSH, shell.PWSH5, shell.GENERIC, shell.ZSH:
+	case shell.PWSH, shell.PWSH5, shell.GENERIC, shell.ZSH, shell.BASH:
 		return true
 	default:
 		return false
@@ -110,8 +111,29 @@ func (e *Engine) writePrimaryRightPrompt() {
 		return
 	}
 
+	if e.Env.Shell() == shell.BASH {
+		e.writeBashRightPrompt()
+		return
+	}
+
 	e.write(terminal.SaveCursorPosition())
 	e.write(strings.Repeat(" ", space))
 	e.write(e.rprompt)
 	e.write(terminal.RestoreCursorPosition())
 }
+
+// writeBashRightPrompt writes the right prompt as a single non-printing sequence so bash doesn't
+// count it in the prompt's width, which would break line editing. Instead of padding, the cursor moves
+// to the right edge of the terminal and back, keeping the right prompt aligned when readline
+// redraws the prompt after the terminal is resized.
+func (e *Engine) writeBashRightPrompt() {
+	// non-printing sequences can't be nested, remove the ones already in the right prompt
+	text := strings.NewReplacer(`\\`, `\\`, `\[`, "", `\]`, "").Replace(e.rprompt)
+
+	var moveLeft string
+	if e.rpromptLength > 1 {
+		moveLeft = fmt.Sprintf("\x1b[%dD", e.rpromptLength-1)
+	}
+
+	e.write(fmt.Sprintf("\\[\x1b7\x1b[999C%s%s\x1b8\\]", moveLeft, text))
+}
@@ -20,10 +20,12 @@ func (e *Engine) RPrompt() string {
 		return ""
 	}
 
+	cycle = &e.Config.Cycle
+
 	text, length := e.writeBlockSegments(rprompt)
 
 	// do not print anything when we don't have any text
-	if length == 0 {
+	if length == 0 || !rprompt.Enabled() {
 		return ""
 	}
 
@@ -31,6 +31,45 @@ func (e *Engine) writeBlockSegments(block *config.Block) (string, int) {
 
 	e.writeSegments(out, block)
 
+	return e.closeBlock(block)
+}
+
+// rewriteBlockSegments writes the already executed segments of a block again,
+// used when one of them changed its text after the block was written.
+func (e *Engine) rewriteBlockSegments(block *config.Block) (string, int) {
+	return e.writeSegmentList(block, block.Segments)
+}
+
+// writeSegmentList writes a selection of the already executed segments of a block
+// as if they were the only ones in it.
+func (e *Engine) writeSegmentList(block *config.Block, segments []*config.Segment) (string, int) {
+	// the block's diamonds are set on the first and last segment written,
+	// restore them afterwards as these can be written in another position later on
+	type diamonds struct {
+		leading  string
+		trailing string
+	}
+
+	original := make([]diamonds, len(segments))
+	for i, segment := range segments {
+		original[i] = diamonds{leading: segment.LeadingDiamond, trailing: segment.TrailingDiamond}
+	}
+
+	defer func() {
+		for i, segment := range segments {
+			segment.LeadingDiamond = original[i].leading
+			segment.TrailingDiamond = original[i].trailing
+		}
+	}()
+
+	for _, segment := range segments {
+		e.writeSegment(block, segment)
+	}
+
+	return e.closeBlock(block)
+}
+
+func (e *Engine) closeBlock(block *config.Block) (string, int) {
 	if e.activeSegment != nil && len(block.TrailingDiamond) > 0 {
 		e.activeSegment.TrailingDiamond = block.TrailingDiamond
 	}
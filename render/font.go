@@ -0,0 +1,167 @@
+package render
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+)
+
+//go:generate go run gen_font.go /usr/share/fonts/truetype/dejavu/DejaVuSansMono.ttf /usr/share/fonts/truetype/font-awesome/fontawesome-webfont.ttf fonts/nerd-subset.bin
+
+// a subset of DejaVu Sans Mono with the Font Awesome 4 icons of Nerd Fonts, see fonts/LICENSE
+//
+//go:embed fonts/nerd-subset.bin
+var fontData []byte
+
+type point struct {
+	X, Y    float64
+	OnCurve bool
+}
+
+type font struct {
+	glyphs     map[rune][][]point
+	unitsPerEm float64
+	ascent     float64
+	descent    float64
+	advance    float64
+}
+
+var (
+	embeddedFont     *font
+	embeddedFontErr  error
+	embeddedFontOnce sync.Once
+)
+
+func loadFont() (*font, error) {
+	embeddedFontOnce.Do(func() {
+		embeddedFont, embeddedFontErr = decodeFont(fontData)
+	})
+
+	return embeddedFont, embeddedFontErr
+}
+
+// decodeFont reads the outlines written by gen_font.go
+func decodeFont(data []byte) (*font, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := bytes.NewReader(raw)
+	read := func(values ...any) error {
+		for _, value := range values {
+			if err := binary.Read(reader, binary.BigEndian, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var magic [4]byte
+	var unitsPerEm, advance uint16
+	var ascent, descent int16
+	var count uint32
+
+	if err := read(&magic, &unitsPerEm, &ascent, &descent, &advance, &count); err != nil {
+		return nil, err
+	}
+
+	if string(magic[:]) != "OMPF" {
+		return nil, errors.New("invalid embedded font")
+	}
+
+	f := &font{
+		glyphs:     make(map[rune][][]point, count),
+		unitsPerEm: float64(unitsPerEm),
+		ascent:     float64(ascent),
+		descent:    float64(descent),
+		advance:    float64(advance),
+	}
+
+	for i := uint32(0); i < count; i++ {
+		var char uint32
+		var numContours uint16
+
+		if err := read(&char, &numContours); err != nil {
+			return nil, err
+		}
+
+		contours := make([][]point, numContours)
+
+		for c := range contours {
+			var numPoints uint16
+			if err := read(&numPoints); err != nil {
+				return nil, err
+			}
+
+			var x, y int16
+
+			contour := make([]point, numPoints)
+
+			for p := range contour {
+				var dx, dy int16
+				var flag uint8
+
+				if err := read(&dx, &dy, &flag); err != nil {
+					return nil, err
+				}
+
+				x += dx
+				y += dy
+				contour[p] = point{X: float64(x), Y: float64(y), OnCurve: flag&1 != 0}
+			}
+
+			contours[c] = contour
+		}
+
+		f.glyphs[rune(char)] = contours
+	}
+
+	return f, nil
+}
+
+// rasterize draws the glyph in a cell of the given size, with the baseline at ascent pixels from the top.
+// The outline is sheared for italic text and drawn twice, one pixel apart, for bold text.
+func (f *font) rasterize(char rune, scale, ascent float64, width, height int, bold, italic bool) (*mask, bool) {
+	contours, OK := f.glyphs[char]
+	if !OK {
+		return nil, false
+	}
+
+	m := newMask(width, height)
+
+	var shear float64
+	if italic {
+		shear = 0.2
+	}
+
+	offsets := []float64{0}
+	if bold {
+		offsets = append(offsets, 1)
+	}
+
+	for _, offset := range offsets {
+		transform := func(p point) (float64, float64) {
+			x := p.X*scale + offset
+			y := ascent - p.Y*scale
+
+			return x + shear*(ascent-y), y
+		}
+
+		for _, contour := range contours {
+			quadraticContour(m, contour, transform)
+		}
+	}
+
+	return m, true
+}
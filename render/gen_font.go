@@ -0,0 +1,467 @@
+//go:build ignore
+
+// gen_font extracts the glyph outlines of a monospace TrueType font into the compact
+// format read by font.go, for the character ranges a prompt typically uses. The Nerd Font
+// icons are taken from Font Awesome 4, which Nerd Fonts ships at its original code points,
+// and scaled to fit the cell of the monospace font.
+//
+//	go run gen_font.go /usr/share/fonts/truetype/dejavu/DejaVuSansMono.ttf /usr/share/fonts/truetype/font-awesome/fontawesome-webfont.ttf fonts/nerd-subset.bin
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+var ranges = [][2]rune{
+	{0x0020, 0x007e}, // ASCII
+	{0x00a0, 0x017f}, // Latin-1 supplement and Latin extended-A
+	{0x0370, 0x03ff}, // Greek
+	{0x2010, 0x203a}, // punctuation
+	{0x2070, 0x209f}, // super- and subscripts
+	{0x20a0, 0x20bf}, // currency
+	{0x2190, 0x21ff}, // arrows
+	{0x2200, 0x22ff}, // mathematical operators
+	{0x2300, 0x23ff}, // miscellaneous technical
+	{0x2500, 0x25ff}, // box drawing, block elements and geometric shapes
+	{0x2600, 0x26ff}, // miscellaneous symbols
+	{0x2700, 0x27bf}, // dingbats
+}
+
+// the Font Awesome range of Nerd Fonts
+var iconRange = [2]rune{0xf000, 0xf2e0}
+
+type point struct {
+	x, y    int16
+	onCurve bool
+}
+
+type font struct {
+	data        []byte
+	tables      map[string][]byte
+	unitsPerEm  uint16
+	longLoca    bool
+	numGlyphs   int
+	ascent      int16
+	descent     int16
+	advance     uint16
+	runeToGlyph map[rune]int
+}
+
+func main() {
+	if len(os.Args) != 4 {
+		fmt.Println("usage: go run gen_font.go <font.ttf> <icons.ttf> <output>")
+		os.Exit(2)
+	}
+
+	f, err := load(os.Args[1])
+	if err != nil {
+		fail(err)
+	}
+
+	icons, err := load(os.Args[2])
+	if err != nil {
+		fail(err)
+	}
+
+	var glyphs bytes.Buffer
+	var count uint32
+
+	add := func(char rune, contours [][]point) {
+		write(&glyphs, uint32(char), uint16(len(contours)))
+
+		for _, contour := range contours {
+			write(&glyphs, uint16(len(contour)))
+
+			// the points are stored relative to the previous one, which compresses better
+			var x, y int16
+
+			for _, p := range contour {
+				var flag uint8
+				if p.onCurve {
+					flag = 1
+				}
+
+				write(&glyphs, p.x-x, p.y-y, flag)
+				x, y = p.x, p.y
+			}
+		}
+
+		count++
+	}
+
+	for _, r := range ranges {
+		for char := r[0]; char <= r[1]; char++ {
+			index, OK := f.runeToGlyph[char]
+			if !OK || index == 0 {
+				continue
+			}
+
+			contours, err := f.glyph(index, 0)
+			if err != nil {
+				fail(fmt.Errorf("U+%04X: %w", char, err))
+			}
+
+			add(char, contours)
+		}
+	}
+
+	// the other Nerd Font sets aren't included, those icons are drawn as a missing glyph
+	// rather than a similar looking Font Awesome icon
+	for char := iconRange[0]; char <= iconRange[1]; char++ {
+		index, OK := icons.runeToGlyph[char]
+		if !OK || index == 0 {
+			continue
+		}
+
+		contours, err := icons.glyph(index, 0)
+		if err != nil {
+			fail(fmt.Errorf("U+%04X: %w", char, err))
+		}
+
+		if len(contours) != 0 {
+			add(char, f.fit(contours))
+		}
+	}
+
+	var out bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&out, gzip.BestCompression)
+	write(zw, []byte("OMPF"), f.unitsPerEm, f.ascent, f.descent, f.advance, count)
+	_, _ = zw.Write(glyphs.Bytes())
+	_ = zw.Close()
+
+	if err := os.WriteFile(os.Args[3], out.Bytes(), 0644); err != nil {
+		fail(err)
+	}
+
+	fmt.Printf("%d glyphs, %d bytes\n", count, out.Len())
+}
+
+func load(file string) (*font, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(data)
+}
+
+// fit scales an icon of another font to a single cell, centered on the middle of the line
+func (f *font) fit(contours [][]point) [][]point {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	for _, contour := range contours {
+		for _, p := range contour {
+			minX, maxX = math.Min(minX, float64(p.x)), math.Max(maxX, float64(p.x))
+			minY, maxY = math.Min(minY, float64(p.y)), math.Max(maxY, float64(p.y))
+		}
+	}
+
+	width, height := maxX-minX, maxY-minY
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	// leave some room between adjacent icons, and keep tall icons within the height of capitals
+	scale := math.Min(float64(f.advance)*0.9/width, float64(f.ascent)*0.8/height)
+
+	centerX := float64(f.advance) / 2
+	centerY := float64(f.ascent+f.descent) / 2
+
+	fitted := make([][]point, len(contours))
+
+	for i, contour := range contours {
+		fitted[i] = make([]point, len(contour))
+
+		for j, p := range contour {
+			fitted[i][j] = point{
+				x:       int16(math.Round(centerX + (float64(p.x)-(minX+maxX)/2)*scale)),
+				y:       int16(math.Round(centerY + (float64(p.y)-(minY+maxY)/2)*scale)),
+				onCurve: p.onCurve,
+			}
+		}
+	}
+
+	return fitted
+}
+
+func write(w interface{ Write([]byte) (int, error) }, values ...any) {
+	for _, value := range values {
+		_ = binary.Write(w, binary.BigEndian, value)
+	}
+}
+
+func fail(err error) {
+	fmt.Println(err)
+	os.Exit(1)
+}
+
+func u16(b []byte, offset int) uint16 {
+	return binary.BigEndian.Uint16(b[offset:])
+}
+
+func u32(b []byte, offset int) uint32 {
+	return binary.BigEndian.Uint32(b[offset:])
+}
+
+func parse(data []byte) (*font, error) {
+	f := &font{
+		data:        data,
+		tables:      make(map[string][]byte),
+		runeToGlyph: make(map[rune]int),
+	}
+
+	numTables := int(u16(data, 4))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		tag := string(data[record : record+4])
+		offset, length := u32(data, record+8), u32(data, record+12)
+		f.tables[tag] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "loca", "glyf"} {
+		if _, OK := f.tables[tag]; !OK {
+			return nil, fmt.Errorf("missing %s table", tag)
+		}
+	}
+
+	head := f.tables["head"]
+	f.unitsPerEm = u16(head, 18)
+	f.longLoca = u16(head, 50) == 1
+
+	hhea := f.tables["hhea"]
+	f.ascent = int16(u16(hhea, 4))
+	f.descent = int16(u16(hhea, 6))
+
+	// a monospace font has the same advance for every glyph
+	f.advance = u16(f.tables["hmtx"], 0)
+	f.numGlyphs = int(u16(f.tables["maxp"], 4))
+
+	return f, f.parseCmap()
+}
+
+func (f *font) parseCmap() error {
+	cmap := f.tables["cmap"]
+
+	numTables := int(u16(cmap, 2))
+	for i := 0; i < numTables; i++ {
+		record := 4 + i*8
+		platform, encoding := u16(cmap, record), u16(cmap, record+2)
+		subtable := cmap[u32(cmap, record+4):]
+
+		if u16(subtable, 0) != 4 || !(platform == 3 && encoding == 1 || platform == 0) {
+			continue
+		}
+
+		segments := int(u16(subtable, 6)) / 2
+		ends := 14
+		starts := ends + segments*2 + 2
+		deltas := starts + segments*2
+		rangeOffsets := deltas + segments*2
+
+		for s := 0; s < segments; s++ {
+			end := rune(u16(subtable, ends+s*2))
+			start := rune(u16(subtable, starts+s*2))
+			delta := u16(subtable, deltas+s*2)
+			rangeOffset := int(u16(subtable, rangeOffsets+s*2))
+
+			for char := start; char <= end && char != 0xffff; char++ {
+				var index uint16
+
+				if rangeOffset == 0 {
+					index = uint16(char) + delta
+				} else {
+					offset := rangeOffsets + s*2 + rangeOffset + int(char-start)*2
+					if index = u16(subtable, offset); index != 0 {
+						index += delta
+					}
+				}
+
+				f.runeToGlyph[char] = int(index)
+			}
+		}
+
+		return nil
+	}
+
+	return errors.New("no unicode BMP cmap")
+}
+
+func (f *font) glyphData(index int) []byte {
+	loca := f.tables["loca"]
+
+	var start, end uint32
+	if f.longLoca {
+		start, end = u32(loca, index*4), u32(loca, index*4+4)
+	} else {
+		start, end = uint32(u16(loca, index*2))*2, uint32(u16(loca, index*2+2))*2
+	}
+
+	return f.tables["glyf"][start:end]
+}
+
+// glyph returns the contours of a glyph, composite glyphs are flattened
+func (f *font) glyph(index, depth int) ([][]point, error) {
+	if depth > 8 {
+		return nil, errors.New("composite glyph nesting too deep")
+	}
+
+	data := f.glyphData(index)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	numContours := int16(u16(data, 0))
+	if numContours < 0 {
+		return f.composite(data, depth)
+	}
+
+	ends := make([]int, numContours)
+	for i := range ends {
+		ends[i] = int(u16(data, 10+i*2))
+	}
+
+	numPoints := 0
+	if numContours > 0 {
+		numPoints = ends[numContours-1] + 1
+	}
+
+	offset := 10 + int(numContours)*2
+	offset += 2 + int(u16(data, offset))
+
+	flags := make([]uint8, 0, numPoints)
+	for len(flags) < numPoints {
+		flag := data[offset]
+		offset++
+		flags = append(flags, flag)
+
+		if flag&8 != 0 {
+			repeat := int(data[offset])
+			offset++
+
+			for i := 0; i < repeat; i++ {
+				flags = append(flags, flag)
+			}
+		}
+	}
+
+	coordinates := func(short, same uint8) []int16 {
+		values := make([]int16, numPoints)
+
+		var value int16
+
+		for i, flag := range flags {
+			switch {
+			case flag&short != 0:
+				delta := int16(data[offset])
+				offset++
+
+				if flag&same == 0 {
+					delta = -delta
+				}
+
+				value += delta
+			case flag&same == 0:
+				value += int16(u16(data, offset))
+				offset += 2
+			}
+
+			values[i] = value
+		}
+
+		return values
+	}
+
+	xs := coordinates(2, 16)
+	ys := coordinates(4, 32)
+
+	contours := make([][]point, 0, numContours)
+	start := 0
+
+	for _, end := range ends {
+		contour := make([]point, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			contour = append(contour, point{xs[i], ys[i], flags[i]&1 != 0})
+		}
+
+		contours = append(contours, contour)
+		start = end + 1
+	}
+
+	return contours, nil
+}
+
+func (f *font) composite(data []byte, depth int) ([][]point, error) {
+	var contours [][]point
+
+	offset := 10
+
+	for {
+		flags := u16(data, offset)
+		index := int(u16(data, offset+2))
+		offset += 4
+
+		var dx, dy int16
+
+		if flags&1 != 0 {
+			dx, dy = int16(u16(data, offset)), int16(u16(data, offset+2))
+			offset += 4
+		} else {
+			dx, dy = int16(int8(data[offset])), int16(int8(data[offset+1]))
+			offset += 2
+		}
+
+		if flags&2 == 0 {
+			return nil, errors.New("composite glyphs with point matching are not supported")
+		}
+
+		// the 2x2 transform, in 2.14 fixed point
+		a, b, c, d := 1.0, 0.0, 0.0, 1.0
+		f2dot14 := func(at int) float64 {
+			return float64(int16(u16(data, at))) / 16384
+		}
+
+		switch {
+		case flags&8 != 0:
+			a = f2dot14(offset)
+			d = a
+			offset += 2
+		case flags&0x40 != 0:
+			a, d = f2dot14(offset), f2dot14(offset+2)
+			offset += 4
+		case flags&0x80 != 0:
+			a, b, c, d = f2dot14(offset), f2dot14(offset+2), f2dot14(offset+4), f2dot14(offset+6)
+			offset += 8
+		}
+
+		component, err := f.glyph(index, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, contour := range component {
+			transformed := make([]point, len(contour))
+
+			for i, p := range contour {
+				x, y := float64(p.x), float64(p.y)
+				transformed[i] = point{
+					x:       int16(a*x+c*y) + dx,
+					y:       int16(b*x+d*y) + dy,
+					onCurve: p.onCurve,
+				}
+			}
+
+			contours = append(contours, transformed)
+		}
+
+		if flags&0x20 == 0 {
+			return contours, nil
+		}
+	}
+}
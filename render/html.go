@@ -0,0 +1,75 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+func writeHTML(w io.Writer, screen *Screen, options *Options) error {
+	theme := options.Theme
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n", html.EscapeString(options.Title))
+	fmt.Fprintf(out, "<body style=\"margin:0;background-color:%s\">\n", hex(theme.Background))
+	fmt.Fprintf(out, "<pre style=\"margin:0;padding:1em;color:%s;background-color:%s;font-family:%s;font-size:%gpx;line-height:1.2\">",
+		hex(theme.Foreground), hex(theme.Background), html.EscapeString(fontFamily), options.FontSize)
+
+	for y, row := range screen.Rows {
+		if y > 0 {
+			out.WriteString("\n")
+		}
+
+		for _, r := range runs(row) {
+			css := spanStyle(r.Style, theme)
+			if len(css) == 0 {
+				out.WriteString(html.EscapeString(r.Text))
+				continue
+			}
+
+			fmt.Fprintf(out, "<span style=\"%s\">%s</span>", html.EscapeString(css), html.EscapeString(r.Text))
+		}
+	}
+
+	out.WriteString("</pre>\n</body>\n</html>\n")
+
+	return out.Flush()
+}
+
+func spanStyle(style Style, theme *Theme) string {
+	if style == (Style{}) {
+		return ""
+	}
+
+	foreground, background := theme.colors(style)
+
+	var css []string
+
+	if foreground != theme.Foreground {
+		css = append(css, "color:"+hex(foreground))
+	}
+
+	if background != theme.Background {
+		css = append(css, "background-color:"+hex(background))
+	}
+
+	if style.Bold {
+		css = append(css, "font-weight:bold")
+	}
+
+	if style.Italic {
+		css = append(css, "font-style:italic")
+	}
+
+	if decoration := decoration(style, theme, foreground); len(decoration) != 0 {
+		css = append(css, decoration)
+	}
+
+	if style.Framed {
+		css = append(css, "outline:1px solid "+hex(foreground), "outline-offset:-1px")
+	}
+
+	return strings.Join(css, ";")
+}
@@ -0,0 +1,217 @@
+package render
+
+import (
+	"image"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/LNKLEO/OMP/color"
+)
+
+type glyphKey struct {
+	char   rune
+	cells  int
+	bold   bool
+	italic bool
+}
+
+type rasterizer struct {
+	font       *font
+	glyphs     map[glyphKey][]float64
+	scale      float64
+	fontSize   float64
+	cellWidth  int
+	cellHeight int
+	baseline   int
+}
+
+// writePNG draws the screen using the embedded font. It contains the common text and symbol ranges and
+// the Font Awesome icons of Nerd Fonts, the powerline separators are drawn as shapes and other characters
+// as a placeholder box.
+func writePNG(w io.Writer, screen *Screen, options *Options) error {
+	f, err := loadFont()
+	if err != nil {
+		return err
+	}
+
+	cellWidth, cellHeight, baseline, err := metrics(options.FontSize)
+	if err != nil {
+		return err
+	}
+
+	r := &rasterizer{
+		font:       f,
+		glyphs:     make(map[glyphKey][]float64),
+		scale:      options.FontSize / f.unitsPerEm,
+		fontSize:   options.FontSize,
+		cellWidth:  int(math.Round(cellWidth)),
+		cellHeight: int(math.Round(cellHeight)),
+		baseline:   int(math.Round(baseline)),
+	}
+
+	theme := options.Theme
+	padding := r.cellWidth
+
+	img := image.NewRGBA(image.Rect(0, 0, screen.Width*r.cellWidth+2*padding, len(screen.Rows)*r.cellHeight+2*padding))
+	fill(img, img.Bounds(), theme.Background)
+
+	cellRect := func(x, y, cells int) image.Rectangle {
+		left := padding + x*r.cellWidth
+		top := padding + y*r.cellHeight
+		return image.Rect(left, top, left+cells*r.cellWidth, top+r.cellHeight)
+	}
+
+	// backgrounds first, glyphs can draw outside of their cell
+	for y, row := range screen.Rows {
+		for x, cell := range row {
+			_, background := theme.colors(cell.Style)
+			fill(img, cellRect(x, y, 1), background)
+		}
+	}
+
+	for y, row := range screen.Rows {
+		for x, cell := range row {
+			if cell.Char == 0 {
+				continue
+			}
+
+			foreground, _ := theme.colors(cell.Style)
+			rect := cellRect(x, y, max(cell.Width, 1))
+
+			if cell.Char != ' ' {
+				coverage := r.glyph(cell.Char, max(cell.Width, 1), cell.Style.Bold, cell.Style.Italic)
+				blendMask(img, rect, coverage, foreground)
+			}
+		}
+
+		for x, cell := range row {
+			foreground, _ := theme.colors(cell.Style)
+			r.decorate(img, cellRect(x, y, 1), row, x, foreground, theme.underlineColor(cell.Style, foreground))
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func (r *rasterizer) glyph(char rune, cells int, bold, italic bool) []float64 {
+	key := glyphKey{char: char, cells: cells, bold: bold, italic: italic}
+	if coverage, OK := r.glyphs[key]; OK {
+		return coverage
+	}
+
+	width := cells * r.cellWidth
+
+	m := newMask(width, r.cellHeight)
+	if !drawPowerline(m, char, float64(width), float64(r.cellHeight)) {
+		var OK bool
+		if m, OK = r.font.rasterize(char, r.scale, float64(r.baseline), width, r.cellHeight, bold, italic); !OK {
+			m = newMask(width, r.cellHeight)
+			drawPlaceholder(m)
+		}
+	}
+
+	coverage := m.coverage()
+	r.glyphs[key] = coverage
+
+	return coverage
+}
+
+// decorate draws the lines of a cell: underlines, strikethrough, overline and frames
+func (r *rasterizer) decorate(img *image.RGBA, rect image.Rectangle, row []Cell, x int, foreground, underline color.RGB) {
+	style := row[x].Style
+	thickness := max(1, int(math.Round(r.fontSize/16)))
+	underlineY := rect.Min.Y + min(r.baseline+int(math.Round(r.fontSize*0.12)), r.cellHeight-thickness)
+
+	line := func(y int, keep func(px int) bool, c color.RGB) {
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			if keep == nil || keep(px) {
+				fill(img, image.Rect(px, y, px+1, y+thickness), c)
+			}
+		}
+	}
+
+	switch style.Underline {
+	case SingleUnderline:
+		line(underlineY, nil, underline)
+	case DoubleUnderline:
+		line(underlineY-thickness, nil, underline)
+		line(underlineY+thickness, nil, underline)
+	case DottedUnderline:
+		line(underlineY, func(px int) bool { return (px/thickness)%2 == 0 }, underline)
+	case DashedUnderline:
+		line(underlineY, func(px int) bool { return (px/(3*thickness))%2 == 0 }, underline)
+	case CurlyUnderline:
+		// the phase depends on the absolute position, so the wave continues across cells
+		amplitude := float64(thickness) + 0.5
+		period := float64(r.cellWidth)
+
+		for px := rect.Min.X; px < rect.Max.X; px++ {
+			y := underlineY + int(math.Round(amplitude*math.Sin(2*math.Pi*float64(px)/period)))
+			fill(img, image.Rect(px, y, px+1, y+thickness), underline)
+		}
+	}
+
+	if style.Strike {
+		line(rect.Min.Y+r.baseline-int(math.Round(r.fontSize*0.3)), nil, foreground)
+	}
+
+	if style.Overline {
+		line(rect.Min.Y, nil, foreground)
+	}
+
+	if !style.Framed {
+		return
+	}
+
+	line(rect.Min.Y, nil, foreground)
+	line(rect.Max.Y-thickness, nil, foreground)
+
+	if x == 0 || !row[x-1].Style.Framed {
+		fill(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+thickness, rect.Max.Y), foreground)
+	}
+
+	if x == len(row)-1 || !row[x+1].Style.Framed {
+		fill(img, image.Rect(rect.Max.X-thickness, rect.Min.Y, rect.Max.X, rect.Max.Y), foreground)
+	}
+}
+
+func fill(img *image.RGBA, rect image.Rectangle, c color.RGB) {
+	rect = rect.Intersect(img.Bounds())
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			offset := img.PixOffset(x, y)
+			img.Pix[offset] = c.R
+			img.Pix[offset+1] = c.G
+			img.Pix[offset+2] = c.B
+			img.Pix[offset+3] = 0xff
+		}
+	}
+}
+
+// blendMask draws the color over the image, using the coverage as opacity
+func blendMask(img *image.RGBA, rect image.Rectangle, coverage []float64, c color.RGB) {
+	width := rect.Dx()
+	bounds := img.Bounds()
+
+	for i, alpha := range coverage {
+		if alpha <= 0 {
+			continue
+		}
+
+		x, y := rect.Min.X+i%width, rect.Min.Y+i/width
+		if !(image.Point{X: x, Y: y}).In(bounds) {
+			continue
+		}
+
+		offset := img.PixOffset(x, y)
+		channel := func(current, value uint8) uint8 {
+			return uint8(float64(current)*(1-alpha) + float64(value)*alpha + 0.5)
+		}
+
+		img.Pix[offset] = channel(img.Pix[offset], c.R)
+		img.Pix[offset+1] = channel(img.Pix[offset+1], c.G)
+		img.Pix[offset+2] = channel(img.Pix[offset+2], c.B)
+	}
+}
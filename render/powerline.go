@@ -0,0 +1,93 @@
+package render
+
+import (
+	"math"
+)
+
+// drawPowerline draws the powerline separators as shapes that fill the cell of the given size,
+// like terminals with built-in powerline support do. The embedded font doesn't have them.
+func drawPowerline(p pen, char rune, w, h float64) bool {
+	thickness := math.Max(1, h/16)
+
+	// a thin line between two points, as a polygon
+	stroke := func(x0, y0, x1, y1 float64) {
+		length := math.Hypot(x1-x0, y1-y0)
+		nx, ny := -(y1-y0)/length*thickness/2, (x1-x0)/length*thickness/2
+		polygon(p, [2]float64{x0 + nx, y0 + ny}, [2]float64{x1 + nx, y1 + ny}, [2]float64{x1 - nx, y1 - ny}, [2]float64{x0 - nx, y0 - ny})
+	}
+
+	// the points of a half ellipse, bulging to the right when right is set
+	arc := func(right bool) [][2]float64 {
+		const steps = 32
+
+		points := make([][2]float64, 0, steps+1)
+
+		for i := 0; i <= steps; i++ {
+			angle := math.Pi * float64(i) / steps
+			x := w * math.Sin(angle)
+			if !right {
+				x = w - x
+			}
+
+			points = append(points, [2]float64{x, h / 2 * (1 - math.Cos(angle))})
+		}
+
+		return points
+	}
+
+	outline := func(points [][2]float64) {
+		for i := 0; i < len(points)-1; i++ {
+			stroke(points[i][0], points[i][1], points[i+1][0], points[i+1][1])
+		}
+	}
+
+	switch char {
+	case 0xe0b0: // right triangle
+		polygon(p, [2]float64{0, 0}, [2]float64{w, h / 2}, [2]float64{0, h})
+	case 0xe0b1: // right angle
+		stroke(0, 0, w, h/2)
+		stroke(w, h/2, 0, h)
+	case 0xe0b2: // left triangle
+		polygon(p, [2]float64{w, 0}, [2]float64{w, h}, [2]float64{0, h / 2})
+	case 0xe0b3: // left angle
+		stroke(w, 0, 0, h/2)
+		stroke(0, h/2, w, h)
+	case 0xe0b4: // right half circle
+		polygon(p, arc(true)...)
+	case 0xe0b5:
+		outline(arc(true))
+	case 0xe0b6: // left half circle
+		polygon(p, arc(false)...)
+	case 0xe0b7:
+		outline(arc(false))
+	case 0xe0b8: // lower left triangle
+		polygon(p, [2]float64{0, 0}, [2]float64{w, h}, [2]float64{0, h})
+	case 0xe0b9, 0xe0bf:
+		stroke(0, 0, w, h)
+	case 0xe0ba: // lower right triangle
+		polygon(p, [2]float64{w, 0}, [2]float64{w, h}, [2]float64{0, h})
+	case 0xe0bb, 0xe0bd:
+		stroke(w, 0, 0, h)
+	case 0xe0bc: // upper left triangle
+		polygon(p, [2]float64{0, 0}, [2]float64{w, 0}, [2]float64{0, h})
+	case 0xe0be: // upper right triangle
+		polygon(p, [2]float64{0, 0}, [2]float64{w, 0}, [2]float64{w, h})
+	default:
+		return false
+	}
+
+	return true
+}
+
+// drawPlaceholder draws a box for characters the embedded font doesn't have
+func drawPlaceholder(m *mask) {
+	w, h := float64(m.width), float64(m.height)
+	inset := math.Round(w / 8)
+	border := math.Max(1, math.Round(h/16))
+
+	left, top, right, bottom := inset, h/4, w-inset, h*7/8
+
+	// the inner rectangle goes the other way around, which cuts it out
+	polygon(m, [2]float64{left, top}, [2]float64{right, top}, [2]float64{right, bottom}, [2]float64{left, bottom})
+	polygon(m, [2]float64{left + border, top + border}, [2]float64{left + border, bottom - border}, [2]float64{right - border, bottom - border}, [2]float64{right - border, top + border})
+}
@@ -0,0 +1,221 @@
+package render
+
+import (
+	"math"
+	"slices"
+)
+
+// mask is an anti-aliased coverage mask. Outlines are drawn by accumulating the signed area
+// each line covers, and the coverage is the running sum of those areas per row.
+// This is the approach of font-rs, which handles overlapping contours like the nonzero rule.
+type mask struct {
+	area   []float64
+	width  int
+	height int
+
+	// the pen position, and where the current contour started
+	x, y           float64
+	startX, startY float64
+}
+
+// pen draws outlines, into a coverage mask for PNG or as path data for SVG
+type pen interface {
+	moveTo(x, y float64)
+	lineTo(x, y float64)
+	quadTo(cx, cy, x, y float64)
+	closePath()
+}
+
+func newMask(width, height int) *mask {
+	return &mask{
+		width:  width,
+		height: height,
+		area:   make([]float64, width*height+2),
+	}
+}
+
+// coverage returns the coverage of each pixel, from 0 to 1
+func (m *mask) coverage() []float64 {
+	coverage := make([]float64, m.width*m.height)
+
+	var sum float64
+
+	for i := range coverage {
+		sum += m.area[i]
+		coverage[i] = math.Min(1, math.Abs(sum))
+	}
+
+	return coverage
+}
+
+func (m *mask) moveTo(x, y float64) {
+	m.x, m.y = x, y
+	m.startX, m.startY = x, y
+}
+
+func (m *mask) lineTo(x, y float64) {
+	m.line(m.x, m.y, x, y)
+	m.x, m.y = x, y
+}
+
+// quadTo flattens a quadratic bezier curve into lines
+func (m *mask) quadTo(cx, cy, x, y float64) {
+	x0, y0 := m.x, m.y
+
+	// the amount of segments depends on how far the curve deviates from a straight line
+	deviation := math.Hypot(x0-2*cx+x, y0-2*cy+y)
+	segments := 1 + int(math.Sqrt(math.Sqrt(3*deviation*deviation)))
+
+	for i := 1; i <= segments; i++ {
+		t := float64(i) / float64(segments)
+		u := 1 - t
+		m.lineTo(u*u*x0+2*u*t*cx+t*t*x, u*u*y0+2*u*t*cy+t*t*y)
+	}
+}
+
+func (m *mask) closePath() {
+	m.lineTo(m.startX, m.startY)
+}
+
+// polygon draws a closed polygon
+func polygon(p pen, points ...[2]float64) {
+	p.moveTo(points[0][0], points[0][1])
+
+	for _, point := range points[1:] {
+		p.lineTo(point[0], point[1])
+	}
+
+	p.closePath()
+}
+
+// quadraticContour draws a TrueType contour, where two consecutive
+// off-curve points imply an on-curve point halfway between them
+func quadraticContour(p pen, contour []point, transform func(point) (float64, float64)) {
+	if len(contour) == 0 {
+		return
+	}
+
+	midpoint := func(a, b point) point {
+		return point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2, OnCurve: true}
+	}
+
+	quadratic := func(control, end point) {
+		cx, cy := transform(control)
+		x, y := transform(end)
+		p.quadTo(cx, cy, x, y)
+	}
+
+	// start on the first on-curve point, or the implied one between the last and first point
+	first := slices.IndexFunc(contour, func(p point) bool { return p.OnCurve })
+
+	var start point
+	if first == -1 {
+		start = midpoint(contour[len(contour)-1], contour[0])
+	} else {
+		start = contour[first]
+	}
+
+	p.moveTo(transform(start))
+
+	var control *point
+
+	for i := 1; i <= len(contour); i++ {
+		next := contour[(first+i+len(contour))%len(contour)]
+
+		switch {
+		case !next.OnCurve && control != nil:
+			quadratic(*control, midpoint(*control, next))
+			control = &next
+		case !next.OnCurve:
+			control = &next
+		case control != nil:
+			quadratic(*control, next)
+			control = nil
+		default:
+			p.lineTo(transform(next))
+		}
+	}
+
+	// close the contour
+	if control != nil {
+		quadratic(*control, start)
+	}
+
+	p.closePath()
+}
+
+// line accumulates the signed area covered to the right of the line
+func (m *mask) line(x0, y0, x1, y1 float64) {
+	clampX := func(x float64) float64 {
+		return math.Max(0, math.Min(float64(m.width)-1e-3, x))
+	}
+
+	x0, x1 = clampX(x0), clampX(x1)
+
+	if y0 == y1 {
+		return
+	}
+
+	direction := 1.0
+	if y0 > y1 {
+		direction = -1
+		x0, y0, x1, y1 = x1, y1, x0, y0
+	}
+
+	dxdy := (x1 - x0) / (y1 - y0)
+	x := x0
+
+	if y0 < 0 {
+		x -= y0 * dxdy
+	}
+
+	for row := int(math.Max(0, y0)); row < m.height && float64(row) < y1; row++ {
+		start := row * m.width
+		dy := math.Min(float64(row+1), y1) - math.Max(float64(row), y0)
+		xNext := x + dxdy*dy
+		d := dy * direction
+
+		left, right := x, xNext
+		if left > right {
+			left, right = right, left
+		}
+
+		leftFloor := math.Floor(left)
+		leftIndex := int(leftFloor)
+		rightCeil := math.Ceil(right)
+		rightIndex := int(rightCeil)
+
+		if rightIndex <= leftIndex+1 {
+			mid := 0.5*(x+xNext) - leftFloor
+			m.area[start+leftIndex] += d - d*mid
+			m.area[start+leftIndex+1] += d * mid
+			x = xNext
+			continue
+		}
+
+		slope := 1 / (right - left)
+		leftFraction := left - leftFloor
+		a0 := 0.5 * slope * (1 - leftFraction) * (1 - leftFraction)
+		rightFraction := right - rightCeil + 1
+		am := 0.5 * slope * rightFraction * rightFraction
+
+		m.area[start+leftIndex] += d * a0
+
+		if rightIndex == leftIndex+2 {
+			m.area[start+leftIndex+1] += d * (1 - a0 - am)
+		} else {
+			a1 := slope * (1.5 - leftFraction)
+			m.area[start+leftIndex+1] += d * (a1 - a0)
+
+			for i := leftIndex + 2; i < rightIndex-1; i++ {
+				m.area[start+i] += d * slope
+			}
+
+			a2 := a1 + float64(rightIndex-leftIndex-3)*slope
+			m.area[start+rightIndex-1] += d * (1 - a2 - am)
+		}
+
+		m.area[start+rightIndex] += d * am
+		x = xNext
+	}
+}
@@ -0,0 +1,159 @@
+// Package render draws the output of a prompt as HTML, SVG or PNG, for previews and documentation.
+// The output is replayed on a minimal terminal emulator first, so the result matches the terminal.
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+)
+
+// Format is an output format
+type Format string
+
+const (
+	HTML Format = "html"
+	SVG  Format = "svg"
+	PNG  Format = "png"
+
+	// the Nerd Fonts most themes are designed with, HTML and SVG rely on them being installed
+	fontFamily = `'CaskaydiaCove Nerd Font', 'MesloLGM Nerd Font', 'FiraCode Nerd Font', 'Hack Nerd Font', 'Symbols Nerd Font', monospace`
+
+	defaultFontSize = 16
+)
+
+// Options configure the output
+type Options struct {
+	Theme    *Theme
+	Title    string
+	FontSize float64
+}
+
+// Render writes the screen in the given format
+func Render(w io.Writer, screen *Screen, format Format, options *Options) error {
+	if options == nil {
+		options = &Options{}
+	}
+
+	if options.Theme == nil {
+		options.Theme = DefaultTheme()
+	}
+
+	if options.FontSize <= 0 {
+		options.FontSize = defaultFontSize
+	}
+
+	switch format {
+	case HTML:
+		return writeHTML(w, screen, options)
+	case SVG:
+		return writeSVG(w, screen, options)
+	case PNG:
+		return writePNG(w, screen, options)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// run is a sequence of cells on a row with the same style
+type run struct {
+	Text   string
+	Style  Style
+	Column int
+	Width  int
+}
+
+func runs(row []Cell) []*run {
+	var result []*run
+	var current *run
+	var text strings.Builder
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+
+		current.Text = text.String()
+		result = append(result, current)
+		text.Reset()
+	}
+
+	for x, cell := range row {
+		// the second half of a wide character
+		if cell.Char == 0 && x > 0 && row[x-1].Width == 2 {
+			current.Width++
+			continue
+		}
+
+		if current == nil || current.Style != cell.Style {
+			flush()
+			current = &run{Style: cell.Style, Column: x}
+		}
+
+		char := cell.Char
+		if char == 0 {
+			char = ' '
+		}
+
+		text.WriteRune(char)
+		current.Width++
+	}
+
+	flush()
+
+	return result
+}
+
+// metrics returns the cell size and the distance from the top of a cell to the baseline
+func metrics(fontSize float64) (width, height, baseline float64, err error) {
+	f, err := loadFont()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	scale := fontSize / f.unitsPerEm
+
+	return f.advance * scale, (f.ascent - f.descent) * scale, f.ascent * scale, nil
+}
+
+func hex(c color.RGB) string {
+	return c.Hex().String()
+}
+
+// decoration returns the CSS text decoration of a style
+func decoration(style Style, theme *Theme, foreground color.RGB) string {
+	var lines []string
+
+	if style.Underline != NoUnderline {
+		lines = append(lines, "underline")
+	}
+
+	if style.Strike {
+		lines = append(lines, "line-through")
+	}
+
+	if style.Overline {
+		lines = append(lines, "overline")
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	decoration := "text-decoration:" + strings.Join(lines, " ")
+
+	switch style.Underline {
+	case DoubleUnderline:
+		decoration += " double"
+	case CurlyUnderline:
+		decoration += " wavy"
+	case DottedUnderline:
+		decoration += " dotted"
+	case DashedUnderline:
+		decoration += " dashed"
+	}
+
+	return decoration + " " + hex(theme.underlineColor(style, foreground))
+}
@@ -0,0 +1,298 @@
+package render
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+
+	"github.com/mattn/go-runewidth"
+)
+
+type colorKind int
+
+const (
+	defaultColor colorKind = iota
+	indexedColor
+	rgbColor
+)
+
+// Color is a color set by an SGR sequence, indexed colors are resolved using the theme
+type Color struct {
+	RGB   color.RGB
+	Kind  colorKind
+	Index uint8
+}
+
+// Underline is the style set by SGR 4 and its sub parameters
+type Underline int
+
+const (
+	NoUnderline Underline = iota
+	SingleUnderline
+	DoubleUnderline
+	CurlyUnderline
+	DottedUnderline
+	DashedUnderline
+)
+
+// Style holds the graphic rendition of a cell
+type Style struct {
+	Foreground     Color
+	Background     Color
+	UnderlineColor Color
+	Underline      Underline
+	Bold           bool
+	Dim            bool
+	Italic         bool
+	Strike         bool
+	Overline       bool
+	Reverse        bool
+	Hidden         bool
+	Framed         bool
+}
+
+// Cell is a position on the screen. Wide characters span two cells,
+// the second one has a width of 0. Empty cells have no character.
+type Cell struct {
+	Style Style
+	Char  rune
+	Width int
+}
+
+// Screen is a minimal terminal emulator, enough to replay the output of a prompt:
+// text, SGR sequences, cursor movement and line breaks. Everything else is ignored.
+type Screen struct {
+	Rows   [][]Cell
+	Width  int
+	style  Style
+	x      int
+	y      int
+	savedX int
+	savedY int
+	// the cursor is past the last column, the next character wraps
+	pendingWrap bool
+}
+
+// NewScreen replays the text on a screen of the given width
+func NewScreen(text string, width int) *Screen {
+	s := &Screen{
+		Width: max(width, 1),
+	}
+
+	s.write(text)
+	s.trim()
+
+	return s
+}
+
+func (s *Screen) write(text string) {
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+
+		switch char {
+		case '\x1b':
+			i = s.escape(runes, i+1)
+		case '\r':
+			s.x = 0
+			s.pendingWrap = false
+		case '\n':
+			// like a terminal with onlcr set
+			s.x = 0
+			s.y++
+			s.pendingWrap = false
+		case '\b':
+			s.x = max(0, s.x-1)
+			s.pendingWrap = false
+		case '\t':
+			s.x = min(s.Width-1, (s.x/8+1)*8)
+		case '\a':
+			continue
+		default:
+			s.put(char)
+		}
+	}
+}
+
+// escape handles the sequence after ESC and returns the index of its last rune
+func (s *Screen) escape(runes []rune, i int) int {
+	if i >= len(runes) {
+		return i
+	}
+
+	switch runes[i] {
+	case '[':
+		return s.csi(runes, i+1)
+	case ']', 'P', '_', '^':
+		// OSC, DCS, APC and PM strings end with BEL or ST
+		for j := i + 1; j < len(runes); j++ {
+			if runes[j] == '\a' {
+				return j
+			}
+
+			if runes[j] == '\x1b' && j+1 < len(runes) && runes[j+1] == '\\' {
+				return j + 1
+			}
+		}
+
+		return len(runes)
+	case '(', ')':
+		// character set designations have one more byte
+		return min(i+1, len(runes))
+	case '7':
+		s.savedX, s.savedY = s.x, s.y
+	case '8':
+		s.x, s.y = s.savedX, s.savedY
+		s.pendingWrap = false
+	}
+
+	return i
+}
+
+func (s *Screen) csi(runes []rune, i int) int {
+	start := i
+
+	// parameter and intermediate bytes, up to the final byte
+	for i < len(runes) && (runes[i] < 0x40 || runes[i] > 0x7e) {
+		i++
+	}
+
+	if i >= len(runes) {
+		return i
+	}
+
+	parameters := string(runes[start:i])
+
+	// private sequences, like showing the cursor, don't change the screen
+	if strings.ContainsAny(parameters, "?<>=") {
+		return i
+	}
+
+	number := func(fallback int) int {
+		first, _, _ := strings.Cut(parameters, ";")
+		value, err := strconv.Atoi(first)
+		if err != nil || value == 0 {
+			return fallback
+		}
+
+		return value
+	}
+
+	switch runes[i] {
+	case 'm':
+		s.style = sgr(s.style, parameters)
+	case 'A':
+		s.y = max(0, s.y-number(1))
+	case 'B':
+		s.y += number(1)
+	case 'C':
+		s.x = min(s.Width-1, s.x+number(1))
+	case 'D':
+		s.x = max(0, s.x-number(1))
+	case 'G':
+		s.x = min(s.Width-1, number(1)-1)
+	case 'H', 'f':
+		row, column, _ := strings.Cut(parameters, ";")
+		y, _ := strconv.Atoi(row)
+		x, _ := strconv.Atoi(column)
+		s.y, s.x = max(0, y-1), min(s.Width-1, max(0, x-1))
+	case 'K':
+		s.eraseLine(number(0))
+	case 's':
+		s.savedX, s.savedY = s.x, s.y
+	case 'u':
+		s.x, s.y = s.savedX, s.savedY
+	}
+
+	s.pendingWrap = false
+
+	return i
+}
+
+func (s *Screen) row(y int) []Cell {
+	for len(s.Rows) <= y {
+		s.Rows = append(s.Rows, make([]Cell, s.Width))
+	}
+
+	return s.Rows[y]
+}
+
+func (s *Screen) put(char rune) {
+	width := runewidth.RuneWidth(char)
+	if width == 0 {
+		return
+	}
+
+	if s.pendingWrap || s.x+width > s.Width {
+		s.x = 0
+		s.y++
+		s.pendingWrap = false
+	}
+
+	row := s.row(s.y)
+	row[s.x] = Cell{Char: char, Width: width, Style: s.style}
+
+	if width == 2 {
+		row[s.x+1] = Cell{Style: s.style}
+	}
+
+	s.x += width
+	if s.x >= s.Width {
+		s.x = s.Width - 1
+		s.pendingWrap = true
+	}
+}
+
+// eraseLine clears the line using the current background
+func (s *Screen) eraseLine(mode int) {
+	row := s.row(s.y)
+
+	from, to := s.x, s.Width
+	switch mode {
+	case 1:
+		from, to = 0, s.x+1
+	case 2:
+		from = 0
+	}
+
+	for x := from; x < to; x++ {
+		row[x] = Cell{Style: Style{Background: s.style.Background}}
+	}
+}
+
+// trim removes the trailing empty rows and columns
+func (s *Screen) trim() {
+	isEmpty := func(cell Cell) bool {
+		return cell.Char == 0 && cell.Style.Background.Kind == defaultColor
+	}
+
+	width := 0
+
+	for _, row := range s.Rows {
+		for x := len(row) - 1; x >= width; x-- {
+			if !isEmpty(row[x]) {
+				width = x + 1
+				break
+			}
+		}
+	}
+
+	isEmptyRow := func(row []Cell) bool {
+		return !slices.ContainsFunc(row, func(cell Cell) bool {
+			return !isEmpty(cell)
+		})
+	}
+
+	for len(s.Rows) != 0 && isEmptyRow(s.Rows[len(s.Rows)-1]) {
+		s.Rows = s.Rows[:len(s.Rows)-1]
+	}
+
+	for i := range s.Rows {
+		s.Rows[i] = s.Rows[i][:width]
+	}
+
+	s.Width = width
+}
@@ -0,0 +1,141 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+)
+
+// sgr applies the parameters of a Select Graphic Rendition sequence to the style.
+// Both the semicolon and the colon separated forms of extended colors are supported.
+func sgr(style Style, parameters string) Style {
+	if len(parameters) == 0 {
+		return Style{}
+	}
+
+	params := strings.Split(parameters, ";")
+
+	number := func(value string) int {
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+
+	for i := 0; i < len(params); i++ {
+		code, sub, hasSub := strings.Cut(params[i], ":")
+
+		// extended colors consume the parameters that follow in the semicolon form
+		extended := func() Color {
+			var args []string
+			if hasSub {
+				args = strings.Split(sub, ":")
+			} else {
+				args = params[i+1:]
+			}
+
+			c, consumed := extendedColor(args, hasSub)
+			if !hasSub {
+				i += consumed
+			}
+
+			return c
+		}
+
+		switch n := number(code); {
+		case n == 0:
+			style = Style{}
+		case n == 1:
+			style.Bold = true
+		case n == 2:
+			style.Dim = true
+		case n == 3:
+			style.Italic = true
+		case n == 4:
+			style.Underline = SingleUnderline
+			if hasSub {
+				style.Underline = Underline(min(number(sub), int(DashedUnderline)))
+			}
+		case n == 7:
+			style.Reverse = true
+		case n == 8:
+			style.Hidden = true
+		case n == 9:
+			style.Strike = true
+		case n == 21:
+			style.Underline = DoubleUnderline
+		case n == 22:
+			style.Bold, style.Dim = false, false
+		case n == 23:
+			style.Italic = false
+		case n == 24:
+			style.Underline = NoUnderline
+		case n == 27:
+			style.Reverse = false
+		case n == 28:
+			style.Hidden = false
+		case n == 29:
+			style.Strike = false
+		case n >= 30 && n <= 37:
+			style.Foreground = Color{Kind: indexedColor, Index: uint8(n - 30)}
+		case n == 38:
+			style.Foreground = extended()
+		case n == 39:
+			style.Foreground = Color{}
+		case n >= 40 && n <= 47:
+			style.Background = Color{Kind: indexedColor, Index: uint8(n - 40)}
+		case n == 48:
+			style.Background = extended()
+		case n == 49:
+			style.Background = Color{}
+		case n == 51, n == 52:
+			style.Framed = true
+		case n == 53:
+			style.Overline = true
+		case n == 54:
+			style.Framed = false
+		case n == 55:
+			style.Overline = false
+		case n == 58:
+			style.UnderlineColor = extended()
+		case n == 59:
+			style.UnderlineColor = Color{}
+		case n >= 90 && n <= 97:
+			style.Foreground = Color{Kind: indexedColor, Index: uint8(n - 90 + 8)}
+		case n >= 100 && n <= 107:
+			style.Background = Color{Kind: indexedColor, Index: uint8(n - 100 + 8)}
+		}
+	}
+
+	return style
+}
+
+// extendedColor parses 5;n and 2;r;g;b, the colon form can have a color space id: 2::r:g:b.
+// It returns the amount of arguments used.
+func extendedColor(args []string, colon bool) (Color, int) {
+	if len(args) == 0 {
+		return Color{}, 0
+	}
+
+	value := func(i int) uint8 {
+		if i >= len(args) {
+			return 0
+		}
+
+		n, _ := strconv.Atoi(args[i])
+		return uint8(n)
+	}
+
+	switch args[0] {
+	case "5":
+		return Color{Kind: indexedColor, Index: value(1)}, 2
+	case "2":
+		offset := 1
+		if colon && len(args) > 4 {
+			offset = 2
+		}
+
+		return Color{Kind: rgbColor, RGB: color.RGB{R: value(offset), G: value(offset + 1), B: value(offset + 2)}}, 4
+	default:
+		return Color{}, 0
+	}
+}
@@ -0,0 +1,152 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+func writeSVG(w io.Writer, screen *Screen, options *Options) error {
+	f, err := loadFont()
+	if err != nil {
+		return err
+	}
+
+	cellWidth, cellHeight, baseline, err := metrics(options.FontSize)
+	if err != nil {
+		return err
+	}
+
+	scale := options.FontSize / f.unitsPerEm
+
+	theme := options.Theme
+	padding := cellWidth
+	width := float64(screen.Width)*cellWidth + 2*padding
+	height := float64(len(screen.Rows))*cellHeight + 2*padding
+
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.2f\" height=\"%.2f\" viewBox=\"0 0 %.2f %.2f\" font-family=\"%s\" font-size=\"%g\">\n",
+		width, height, width, height, html.EscapeString(fontFamily), options.FontSize)
+
+	if len(options.Title) != 0 {
+		fmt.Fprintf(out, "<title>%s</title>\n", html.EscapeString(options.Title))
+	}
+
+	fmt.Fprintf(out, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", hex(theme.Background))
+
+	for y, row := range screen.Rows {
+		top := padding + float64(y)*cellHeight
+
+		// the icons are drawn as paths, the machine displaying the SVG rarely has a Nerd Font installed
+		text := slices.Clone(row)
+		var icons []string
+
+		for x, cell := range row {
+			path := &svgPath{left: padding + float64(x)*cellWidth, top: top}
+			if !drawIcon(path, f, cell.Char, float64(max(cell.Width, 1))*cellWidth, cellHeight, baseline, scale) {
+				continue
+			}
+
+			text[x].Char = ' '
+			foreground, _ := theme.colors(cell.Style)
+			icons = append(icons, fmt.Sprintf("<path d=\"%s\" fill=\"%s\"/>\n", path, hex(foreground)))
+		}
+
+		for _, r := range runs(text) {
+			foreground, background := theme.colors(r.Style)
+			left := padding + float64(r.Column)*cellWidth
+			runWidth := float64(r.Width) * cellWidth
+
+			if background != theme.Background {
+				// a little wider, so adjacent runs don't leave hairline gaps when scaled
+				fmt.Fprintf(out, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"%s\"/>\n", left, top, runWidth+0.5, cellHeight+0.5, hex(background))
+			}
+
+			if r.Style.Framed {
+				fmt.Fprintf(out, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"none\" stroke=\"%s\"/>\n", left+0.5, top+0.5, runWidth-1, cellHeight-1, hex(foreground))
+			}
+
+			if len(strings.TrimSpace(r.Text)) == 0 && r.Style.Underline == NoUnderline && !r.Style.Strike && !r.Style.Overline {
+				continue
+			}
+
+			fmt.Fprintf(out, "<text x=\"%.2f\" y=\"%.2f\" fill=\"%s\" textLength=\"%.2f\" lengthAdjust=\"spacingAndGlyphs\" xml:space=\"preserve\"", left, top+baseline, hex(foreground), runWidth)
+
+			if r.Style.Bold {
+				out.WriteString(" font-weight=\"bold\"")
+			}
+
+			if r.Style.Italic {
+				out.WriteString(" font-style=\"italic\"")
+			}
+
+			if decoration := decoration(r.Style, theme, foreground); len(decoration) != 0 {
+				fmt.Fprintf(out, " style=\"%s\"", html.EscapeString(decoration))
+			}
+
+			fmt.Fprintf(out, ">%s</text>\n", html.EscapeString(r.Text))
+		}
+
+		for _, icon := range icons {
+			out.WriteString(icon)
+		}
+	}
+
+	out.WriteString("</svg>\n")
+
+	return out.Flush()
+}
+
+// drawIcon draws a powerline separator or a Nerd Font icon of the embedded font in a cell of the given size
+func drawIcon(p pen, f *font, char rune, width, height, baseline, scale float64) bool {
+	if drawPowerline(p, char, width, height) {
+		return true
+	}
+
+	if !unicode.Is(unicode.Co, char) {
+		return false
+	}
+
+	contours, OK := f.glyphs[char]
+	if !OK {
+		return false
+	}
+
+	transform := func(p point) (float64, float64) {
+		return p.X * scale, baseline - p.Y*scale
+	}
+
+	for _, contour := range contours {
+		quadraticContour(p, contour, transform)
+	}
+
+	return true
+}
+
+// svgPath writes an outline as SVG path data, the coordinates are relative to the cell
+type svgPath struct {
+	strings.Builder
+	left float64
+	top  float64
+}
+
+func (p *svgPath) moveTo(x, y float64) {
+	fmt.Fprintf(p, "M%.2f %.2f", p.left+x, p.top+y)
+}
+
+func (p *svgPath) lineTo(x, y float64) {
+	fmt.Fprintf(p, "L%.2f %.2f", p.left+x, p.top+y)
+}
+
+func (p *svgPath) quadTo(cx, cy, x, y float64) {
+	fmt.Fprintf(p, "Q%.2f %.2f %.2f %.2f", p.left+cx, p.top+cy, p.left+x, p.top+y)
+}
+
+func (p *svgPath) closePath() {
+	p.WriteString("Z")
+}
@@ -0,0 +1,134 @@
+package render
+
+import (
+	"github.com/LNKLEO/OMP/color"
+
+	gookit "github.com/gookit/color"
+)
+
+// Theme holds the terminal colors used for the default and indexed colors
+type Theme struct {
+	Foreground color.RGB
+	Background color.RGB
+	ANSI       [16]color.RGB
+}
+
+// the palette keys written by color.ImportPalette
+var themeKeys = [16]color.Ansi{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"darkGray", "lightRed", "lightGreen", "lightYellow", "lightBlue", "lightMagenta", "lightCyan", "lightWhite",
+}
+
+// DefaultTheme uses the Campbell colors of Windows Terminal
+func DefaultTheme() *Theme {
+	return &Theme{
+		Foreground: color.RGB{R: 204, G: 204, B: 204},
+		Background: color.RGB{R: 12, G: 12, B: 12},
+		ANSI: [16]color.RGB{
+			{R: 12, G: 12, B: 12},
+			{R: 197, G: 15, B: 31},
+			{R: 19, G: 161, B: 14},
+			{R: 193, G: 156, B: 0},
+			{R: 0, G: 55, B: 218},
+			{R: 136, G: 23, B: 152},
+			{R: 58, G: 150, B: 221},
+			{R: 204, G: 204, B: 204},
+			{R: 118, G: 118, B: 118},
+			{R: 231, G: 72, B: 86},
+			{R: 22, G: 198, B: 12},
+			{R: 249, G: 241, B: 165},
+			{R: 59, G: 120, B: 255},
+			{R: 180, G: 0, B: 158},
+			{R: 97, G: 214, B: 214},
+			{R: 242, G: 242, B: 242},
+		},
+	}
+}
+
+// ThemeFromFile reads the colors of a terminal theme, the colors it doesn't define use the defaults
+func ThemeFromFile(file string) (*Theme, error) {
+	palette, err := color.ImportPalette(file, "")
+	if err != nil {
+		return nil, err
+	}
+
+	theme := DefaultTheme()
+
+	set := func(key color.Ansi, target *color.RGB) {
+		values := gookit.HexToRgb(palette[key].String())
+		if len(values) != 3 {
+			return
+		}
+
+		*target = color.RGB{R: uint8(values[0]), G: uint8(values[1]), B: uint8(values[2])}
+	}
+
+	for i, key := range themeKeys {
+		set(key, &theme.ANSI[i])
+	}
+
+	set("foreground", &theme.Foreground)
+	set("background", &theme.Background)
+
+	return theme, nil
+}
+
+func (t *Theme) resolve(c Color, fallback color.RGB) color.RGB {
+	switch c.Kind {
+	case rgbColor:
+		return c.RGB
+	case indexedColor:
+		if c.Index < 16 {
+			return t.ANSI[c.Index]
+		}
+
+		return xterm256(c.Index)
+	default:
+		return fallback
+	}
+}
+
+// colors returns the foreground and background of a style, after applying reverse, dim and hidden
+func (t *Theme) colors(style Style) (foreground, background color.RGB) {
+	foreground = t.resolve(style.Foreground, t.Foreground)
+	background = t.resolve(style.Background, t.Background)
+
+	if style.Reverse {
+		foreground, background = background, foreground
+	}
+
+	if style.Dim {
+		foreground = blend(background, foreground, 0.5)
+	}
+
+	if style.Hidden {
+		foreground = background
+	}
+
+	return foreground, background
+}
+
+func (t *Theme) underlineColor(style Style, foreground color.RGB) color.RGB {
+	return t.resolve(style.UnderlineColor, foreground)
+}
+
+func xterm256(index uint8) color.RGB {
+	if index >= 232 {
+		gray := 8 + (index-232)*10
+		return color.RGB{R: gray, G: gray, B: gray}
+	}
+
+	levels := [6]uint8{0, 95, 135, 175, 215, 255}
+	index -= 16
+
+	return color.RGB{R: levels[index/36], G: levels[(index/6)%6], B: levels[index%6]}
+}
+
+// blend mixes the color into the base color by the given amount
+func blend(base, c color.RGB, amount float64) color.RGB {
+	channel := func(a, b uint8) uint8 {
+		return uint8(float64(a)*(1-amount) + float64(b)*amount + 0.5)
+	}
+
+	return color.RGB{R: channel(base.R, c.R), G: channel(base.G, c.G), B: channel(base.B, c.B)}
+}
@@ -57,6 +57,7 @@ type Environment interface {
 	IsCygwin() bool
 	StackCount() int
 	TerminalWidth() (int, error)
+	TerminalBackground() string
 	Cache() cache.Cache
 	Session() cache.Cache
 	Close()
@@ -67,35 +68,39 @@ type Environment interface {
 	Connection() ([]*Connection, error)
 	CursorPosition() (row, col int)
 	SystemInfo() (*SystemInfo, error)
+	DiskUsage(path string) (*disk.UsageStat, error)
 }
 
 type Flags struct {
-	PSWD          string
-	PipeStatus    string
-	Config        string
-	Shell         string
-	ShellVersion  string
-	PWD           string
-	AbsolutePWD   string
-	Type          string
-	ErrorCode     int
-	PromptCount   int
-	StackCount    int
-	Column        int
-	TerminalWidth int
-	ExecutionTime float64
-	JobCount      int
-	IsPrimary     bool
-	HasExtra      bool
-	Debug         bool
-	Plain         bool
-	Strict        bool
-	Cleared       bool
-	NoExitCode    bool
-	SaveCache     bool
-	Init          bool
-	Migrate       bool
-	Eval          bool
+	PSWD                    string
+	PipeStatus              string
+	Config                  string
+	Shell                   string
+	ShellVersion            string
+	PWD                     string
+	AbsolutePWD             string
+	Type                    string
+	ColorProfile            string
+	Mocks                   map[string]string
+	ErrorCode               int
+	PromptCount             int
+	StackCount              int
+	Column                  int
+	TerminalWidth           int
+	ExecutionTime           float64
+	JobCount                int
+	IsPrimary               bool
+	HasExtra                bool
+	Debug                   bool
+	Plain                   bool
+	Strict                  bool
+	Cleared                 bool
+	NoExitCode              bool
+	SaveCache               bool
+	Init                    bool
+	Migrate                 bool
+	Eval                    bool
+	QueryTerminalBackground bool
 }
 
 type CommandError struct {
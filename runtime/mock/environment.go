@@ -10,6 +10,7 @@ import (
 	"github.com/LNKLEO/OMP/runtime/battery"
 	"github.com/LNKLEO/OMP/runtime/http"
 
+	disk "github.com/shirou/gopsutil/v3/disk"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -177,6 +178,11 @@ func (env *Environment) TerminalWidth() (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (env *Environment) TerminalBackground() string {
+	args := env.Called()
+	return args.String(0)
+}
+
 func (env *Environment) CachePath() string {
 	args := env.Called()
 	return args.String(0)
@@ -277,6 +283,11 @@ func (env *Environment) SystemInfo() (*runtime.SystemInfo, error) {
 	return args.Get(0).(*runtime.SystemInfo), args.Error(1)
 }
 
+func (env *Environment) DiskUsage(path string) (*disk.UsageStat, error) {
+	args := env.Called(path)
+	return args.Get(0).(*disk.UsageStat), args.Error(1)
+}
+
 func (env *Environment) Unset(name string) {
 	for i := 0; i < len(env.ExpectedCalls); i++ {
 		f := env.ExpectedCalls[i]
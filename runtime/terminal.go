@@ -614,6 +614,18 @@ func (term *Terminal) SystemInfo() (*SystemInfo, error) {
 	return s, nil
 }
 
+func (term *Terminal) DiskUsage(path string) (*disk.UsageStat, error) {
+	defer log.Trace(time.Now(), path)
+
+	usage, err := disk.Usage(path)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return usage, nil
+}
+
 func cleanHostName(hostName string) string {
 	garbage := []string{
 		".lan",
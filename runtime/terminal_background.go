@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LNKLEO/OMP/cache"
+	"github.com/LNKLEO/OMP/log"
+)
+
+const (
+	terminalBackgroundCache = "terminal_background"
+	// terminals answering the OSC 11 query do so almost instantly
+	terminalBackgroundTimeout = 200 * time.Millisecond
+	// when the terminal doesn't answer, query again after a while rather than on every prompt
+	terminalBackgroundRetry = cache.Duration("5m")
+)
+
+// TerminalBackground returns the background color of the terminal, as a hex color or an ANSI color index.
+// Unless disabled, the terminal is queried using OSC 11, falling back to COLORFGBG. The answer of the terminal
+// is cached for the session, the fallback only for a few minutes.
+func (term *Terminal) TerminalBackground() string {
+	defer log.Trace(time.Now())
+
+	// the query switches the terminal to raw mode, which blocks and swallows typeahead
+	if !term.CmdFlags.QueryTerminalBackground {
+		return parseColorFgBg(term.Getenv("COLORFGBG"))
+	}
+
+	if background, OK := term.Session().Get(terminalBackgroundCache); OK {
+		return background
+	}
+
+	if background := term.queryTerminalBackground(); len(background) != 0 {
+		log.Debug("terminal background:", background)
+		term.Session().Set(terminalBackgroundCache, background, cache.INFINITE)
+		return background
+	}
+
+	background := parseColorFgBg(term.Getenv("COLORFGBG"))
+
+	log.Debug("terminal background from COLORFGBG:", background)
+
+	term.Session().Set(terminalBackgroundCache, background, terminalBackgroundRetry)
+
+	return background
+}
+
+func (term *Terminal) queryTerminalBackground() string {
+	if term.CmdFlags.Plain {
+		return ""
+	}
+
+	response, err := queryTerminal("\x1b]11;?\x1b\\", terminalBackgroundTimeout)
+	if err != nil {
+		log.Debug(err.Error())
+		return ""
+	}
+
+	return parseOSC11(response)
+}
+
+// parseOSC11 extracts the color from a response like ESC ] 11 ; rgb:ffff/ffff/ffff ESC \,
+// each channel has 1 to 4 hex digits.
+func parseOSC11(response string) string {
+	_, value, found := strings.Cut(response, "]11;rgb:")
+	if !found {
+		return ""
+	}
+
+	value = strings.TrimRight(strings.SplitN(value, "\x1b", 2)[0], "\a")
+
+	channels := strings.Split(value, "/")
+	if len(channels) != 3 {
+		return ""
+	}
+
+	hex := "#"
+
+	for _, channel := range channels {
+		if len(channel) == 0 || len(channel) > 4 {
+			return ""
+		}
+
+		number, err := strconv.ParseUint(channel, 16, 16)
+		if err != nil {
+			return ""
+		}
+
+		scale := uint64(1)<<(4*len(channel)) - 1
+		hex += fmt.Sprintf("%02x", number*255/scale)
+	}
+
+	return hex
+}
+
+// parseColorFgBg reads the background from COLORFGBG, formatted as "fg;bg" or "fg;default;bg".
+func parseColorFgBg(value string) string {
+	if len(value) == 0 {
+		return ""
+	}
+
+	fields := strings.Split(value, ";")
+	background := fields[len(fields)-1]
+
+	index, err := strconv.Atoi(background)
+	if err != nil || index < 0 || index > 15 {
+		return ""
+	}
+
+	return background
+}
+
+// IsDarkColor tells whether a background color, as returned by TerminalBackground, is dark.
+func IsDarkColor(background string) bool {
+	if !strings.HasPrefix(background, "#") {
+		// the rxvt convention for COLORFGBG
+		index, err := strconv.Atoi(background)
+		if err != nil {
+			return true
+		}
+
+		return index < 7 || index == 8
+	}
+
+	value, err := strconv.ParseUint(strings.TrimPrefix(background, "#"), 16, 32)
+	if err != nil {
+		return true
+	}
+
+	r, g, b := float64(value>>16&0xff), float64(value>>8&0xff), float64(value&0xff)
+
+	// perceived brightness, ITU-R BT.601
+	return 0.299*r+0.587*g+0.114*b < 128
+}
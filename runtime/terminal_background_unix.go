@@ -0,0 +1,72 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package runtime
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// queryTerminal writes an escape sequence to the terminal and reads the response. The device attributes
+// query is sent right after it, all terminals answer that one, so we don't wait for the timeout
+// when the terminal doesn't support the first query.
+func queryTerminal(query string, timeout time.Duration) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return "", err
+	}
+
+	defer tty.Close()
+
+	fd := int(tty.Fd())
+
+	original, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return "", errors.New("not a terminal")
+	}
+
+	raw := *original
+	raw.Lflag &^= unix.ECHO | unix.ICANON
+	raw.Cc[unix.VMIN] = 0
+	raw.Cc[unix.VTIME] = 1
+
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, &raw); err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = unix.IoctlSetTermios(fd, ioctlWriteTermios, original)
+	}()
+
+	if _, err := tty.WriteString(query + "\x1b[c"); err != nil {
+		return "", err
+	}
+
+	var response strings.Builder
+	buffer := make([]byte, 64)
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		n, err := tty.Read(buffer)
+		if err != nil {
+			break
+		}
+
+		response.Write(buffer[:n])
+
+		// the device attributes response ends the answer: ESC [ ? ... c
+		if _, attributes, found := strings.Cut(response.String(), "\x1b[?"); found && strings.Contains(attributes, "c") {
+			break
+		}
+	}
+
+	if response.Len() == 0 {
+		return "", errors.New("no response from terminal")
+	}
+
+	return response.String(), nil
+}
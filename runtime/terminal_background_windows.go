@@ -0,0 +1,10 @@
+package runtime
+
+import (
+	"time"
+)
+
+// queryTerminal isn't supported, the console would need to switch to virtual terminal input
+func queryTerminal(_ string, _ time.Duration) (string, error) {
+	return "", &NotImplemented{}
+}
@@ -0,0 +1,209 @@
+package segments
+
+import (
+	"crypto/sha1" //nolint:gosec // the AWS CLI uses SHA1 to name its SSO cache files
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/LNKLEO/OMP/log"
+	"github.com/LNKLEO/OMP/properties"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// FetchSSOExpiry reads the SSO token cache to determine when the session expires
+	FetchSSOExpiry properties.Property = "fetch_sso_expiry"
+
+	awsDefaultProfile = "default"
+)
+
+type Aws struct {
+	base
+
+	SSOExpiresAt   time.Time
+	Profile        string
+	Region         string
+	AccountID      string
+	Role           string
+	SSOExpiresIn   time.Duration
+	HasCredentials bool
+	SSO            bool
+	SSOExpired     bool
+}
+
+type awsSSOToken struct {
+	ExpiresAt string `json:"expiresAt"`
+}
+
+func (a *Aws) Template() string {
+	return " {{ .Profile }}{{ if .Region }}@{{ .Region }}{{ end }} "
+}
+
+func (a *Aws) Enabled() bool {
+	getEnvFirstMatch := func(envs ...string) string {
+		for _, env := range envs {
+			if value := a.env.Getenv(env); len(value) != 0 {
+				return value
+			}
+		}
+		return ""
+	}
+
+	displayDefault := a.props.GetBool(properties.DisplayDefault, true)
+
+	a.Profile = getEnvFirstMatch("AWS_VAULT", "AWS_PROFILE", "AWS_DEFAULT_PROFILE")
+	a.Region = getEnvFirstMatch("AWS_REGION", "AWS_DEFAULT_REGION")
+
+	// without a profile, the CLI falls back to the default one
+	// but only when there's something to show for it
+	explicitProfile := len(a.Profile) != 0
+	if !explicitProfile {
+		a.Profile = awsDefaultProfile
+	}
+
+	if !displayDefault && a.Profile == awsDefaultProfile {
+		return false
+	}
+
+	foundConfig := a.parseConfigFile()
+	a.HasCredentials = a.parseCredentialsFile()
+
+	if !explicitProfile && !foundConfig && !a.HasCredentials && len(a.Region) == 0 {
+		return false
+	}
+
+	return true
+}
+
+func (a *Aws) configDir() string {
+	return filepath.Join(a.env.Home(), ".aws")
+}
+
+func (a *Aws) loadIni(envVar, fileName string) *ini.File {
+	file := a.env.Getenv(envVar)
+	if len(file) == 0 {
+		file = filepath.Join(a.configDir(), fileName)
+	}
+
+	content := a.env.FileContent(file)
+	if len(content) == 0 {
+		return nil
+	}
+
+	cfg, err := ini.Load([]byte(content))
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+
+	return cfg
+}
+
+func (a *Aws) parseConfigFile() bool {
+	cfg := a.loadIni("AWS_CONFIG_FILE", "config")
+	if cfg == nil {
+		return false
+	}
+
+	sectionName := "profile " + a.Profile
+	if a.Profile == awsDefaultProfile {
+		sectionName = awsDefaultProfile
+	}
+
+	section, err := cfg.GetSection(sectionName)
+	if err != nil {
+		return false
+	}
+
+	if len(a.Region) == 0 {
+		a.Region = section.Key("region").String()
+	}
+
+	a.AccountID = section.Key("sso_account_id").String()
+	a.Role = section.Key("sso_role_name").String()
+
+	if roleArn := section.Key("role_arn").String(); len(roleArn) != 0 && len(a.Role) == 0 {
+		a.Role = roleArn
+	}
+
+	a.parseSSOSession(cfg, section)
+
+	return true
+}
+
+func (a *Aws) parseCredentialsFile() bool {
+	cfg := a.loadIni("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+	if cfg == nil {
+		return false
+	}
+
+	section, err := cfg.GetSection(a.Profile)
+	if err != nil {
+		return false
+	}
+
+	return len(section.Key("aws_access_key_id").String()) != 0
+}
+
+func (a *Aws) parseSSOSession(cfg *ini.File, profile *ini.Section) {
+	// the cache file is named after the sso-session name when using the
+	// newer token provider configuration, or after the start URL for legacy profiles
+	cacheKey := profile.Key("sso_start_url").String()
+
+	if session := profile.Key("sso_session").String(); len(session) != 0 {
+		cacheKey = session
+
+		if ssoSection, err := cfg.GetSection("sso-session " + session); err == nil && len(a.Region) == 0 {
+			a.Region = ssoSection.Key("sso_region").String()
+		}
+	}
+
+	if len(cacheKey) == 0 {
+		return
+	}
+
+	a.SSO = true
+
+	if !a.props.GetBool(FetchSSOExpiry, true) {
+		return
+	}
+
+	hash := sha1.Sum([]byte(cacheKey)) //nolint:gosec
+	cacheFile := filepath.Join(a.configDir(), "sso", "cache", hex.EncodeToString(hash[:])+".json")
+
+	content := a.env.FileContent(cacheFile)
+	if len(content) == 0 {
+		// no cached token means we never logged in
+		a.SSOExpired = true
+		return
+	}
+
+	var token awsSSOToken
+	if err := json.Unmarshal([]byte(content), &token); err != nil {
+		log.Error(err)
+		return
+	}
+
+	expiresAt, err := parseAwsTime(token.ExpiresAt)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	a.SSOExpiresAt = expiresAt
+	a.SSOExpiresIn = time.Until(expiresAt).Round(time.Second)
+	a.SSOExpired = a.SSOExpiresIn <= 0
+}
+
+func parseAwsTime(value string) (time.Time, error) {
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return expiresAt, nil
+	}
+
+	// older versions of the CLI wrote the time zone as a literal
+	return time.Parse("2006-01-02T15:04:05UTC", value)
+}
@@ -0,0 +1,32 @@
+package segments
+
+type Bun struct {
+	NodeWorkspace
+	language
+}
+
+func (b *Bun) Template() string {
+	return " \U000F0CD3 {{ .Full }} "
+}
+
+func (b *Bun) Enabled() bool {
+	b.extensions = []string{"bun.lockb", "bun.lock", "bunfig.toml"}
+	b.commands = []*cmd{
+		{
+			executable: "bun",
+			args:       []string{"--version"},
+			regex:      `(?P<version>((?P<major>[0-9]+).(?P<minor>[0-9]+).(?P<patch>[0-9]+)))`,
+		},
+	}
+	b.versionURLTemplate = "https://github.com/oven-sh/bun/releases/tag/bun-v{{ .Full }}"
+
+	if !b.language.Enabled() {
+		return false
+	}
+
+	if b.props.GetBool(FetchPackageWorkspace, false) {
+		b.NodeWorkspace, _ = b.language.nodeWorkspace()
+	}
+
+	return true
+}
@@ -0,0 +1,32 @@
+package segments
+
+type Deno struct {
+	NodeWorkspace
+	language
+}
+
+func (d *Deno) Template() string {
+	return " \ue7c0 {{ .Full }} "
+}
+
+func (d *Deno) Enabled() bool {
+	d.extensions = []string{"deno.json", "deno.jsonc", "deno.lock"}
+	d.commands = []*cmd{
+		{
+			executable: "deno",
+			args:       []string{"--version"},
+			regex:      `(?:deno (?P<version>((?P<major>[0-9]+).(?P<minor>[0-9]+).(?P<patch>[0-9]+))))`,
+		},
+	}
+	d.versionURLTemplate = "https://github.com/denoland/deno/releases/tag/v{{ .Full }}"
+
+	if !d.language.Enabled() {
+		return false
+	}
+
+	if d.props.GetBool(FetchPackageWorkspace, false) {
+		d.NodeWorkspace, _ = d.language.nodeWorkspace()
+	}
+
+	return true
+}
@@ -0,0 +1,200 @@
+package segments
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/LNKLEO/OMP/properties"
+	"github.com/LNKLEO/OMP/runtime"
+)
+
+const (
+	EnvCodespace    = "codespace"
+	EnvDevContainer = "devcontainer"
+	EnvToolbox      = "toolbox"
+	EnvDistrobox    = "distrobox"
+	EnvPodman       = "podman"
+	EnvDocker       = "docker"
+	EnvKubernetes   = "kubernetes"
+	EnvLXC          = "lxc"
+	EnvContainer    = "container"
+	EnvNixShell     = "nix"
+	EnvDirenv       = "direnv"
+
+	containerEnvFile = "/run/.containerenv"
+)
+
+type Environment struct {
+	base
+
+	Kind  string
+	Name  string
+	Image string
+	Icon  string
+	// Kinds contains all detected environments, ordered from most to least specific
+	Kinds       []string
+	InContainer bool
+}
+
+func (e *Environment) Template() string {
+	return " {{ .Icon }} {{ if .Name }}{{ .Name }}{{ else }}{{ .Kind }}{{ end }} "
+}
+
+func (e *Environment) Enabled() bool {
+	e.detectCodespace()
+	e.detectDevContainer()
+	e.detectContainer()
+	e.detectNixShell()
+	e.detectDirenv()
+
+	if len(e.Kinds) == 0 {
+		return false
+	}
+
+	e.Kind = e.Kinds[0]
+	e.Icon = e.props.GetString(properties.Property(e.Kind+"_icon"), e.defaultIcon(e.Kind))
+
+	return true
+}
+
+func (e *Environment) add(kind, name, image string) {
+	e.Kinds = append(e.Kinds, kind)
+
+	if len(e.Name) == 0 {
+		e.Name = name
+	}
+
+	if len(e.Image) == 0 {
+		e.Image = image
+	}
+}
+
+func (e *Environment) detectCodespace() {
+	if e.env.Getenv("CODESPACES") != "true" {
+		return
+	}
+
+	e.InContainer = true
+	e.add(EnvCodespace, e.env.Getenv("CODESPACE_NAME"), "")
+}
+
+func (e *Environment) detectDevContainer() {
+	if e.env.Getenv("REMOTE_CONTAINERS") != "true" {
+		return
+	}
+
+	e.InContainer = true
+	e.add(EnvDevContainer, e.env.Getenv("DEVCONTAINER_NAME"), "")
+}
+
+func (e *Environment) detectContainer() {
+	if e.env.GOOS() != runtime.LINUX {
+		return
+	}
+
+	name, image := e.parseContainerEnv()
+
+	switch {
+	case len(e.env.Getenv("DISTROBOX_ENTER_PATH")) != 0 || e.env.Getenv("container") == "distrobox":
+		e.add(EnvDistrobox, e.env.Getenv("CONTAINER_ID"), image)
+	case e.env.HasFilesInDir("/run", ".toolboxenv"):
+		e.add(EnvToolbox, name, image)
+	case e.env.HasFilesInDir("/run", ".containerenv"):
+		e.add(EnvPodman, name, image)
+	case e.env.HasFilesInDir("/", ".dockerenv"):
+		e.add(EnvDocker, e.env.Getenv("HOSTNAME"), "")
+	default:
+		kind := e.cgroupContainerKind()
+		if len(kind) == 0 {
+			return
+		}
+
+		e.add(kind, e.env.Getenv("HOSTNAME"), "")
+	}
+
+	e.InContainer = true
+}
+
+// parseContainerEnv reads the key/value pairs podman writes into /run/.containerenv
+func (e *Environment) parseContainerEnv() (name, image string) {
+	content := e.env.FileContent(containerEnvFile)
+	if len(content) == 0 {
+		return "", ""
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = value
+		case "image":
+			image = value
+		}
+	}
+
+	return name, image
+}
+
+func (e *Environment) cgroupContainerKind() string {
+	if len(e.env.Getenv("KUBERNETES_SERVICE_HOST")) != 0 {
+		return EnvKubernetes
+	}
+
+	cgroup := e.env.FileContent("/proc/1/cgroup")
+
+	switch {
+	case strings.Contains(cgroup, "kubepods"):
+		return EnvKubernetes
+	case strings.Contains(cgroup, "docker"):
+		return EnvDocker
+	case strings.Contains(cgroup, "lxc"):
+		return EnvLXC
+	case strings.Contains(cgroup, "containerd"), strings.Contains(cgroup, "libpod"):
+		return EnvContainer
+	}
+
+	return ""
+}
+
+func (e *Environment) detectNixShell() {
+	if len(e.env.Getenv("IN_NIX_SHELL")) == 0 {
+		return
+	}
+
+	// nix-shell and nix develop export the derivation name as $name
+	e.add(EnvNixShell, e.env.Getenv("name"), "")
+}
+
+func (e *Environment) detectDirenv() {
+	dir := e.env.Getenv("DIRENV_DIR")
+	if len(dir) == 0 {
+		return
+	}
+
+	// direnv prefixes the folder with a dash
+	dir = strings.TrimPrefix(dir, "-")
+	e.add(EnvDirenv, filepath.Base(dir), "")
+}
+
+func (e *Environment) defaultIcon(kind string) string {
+	switch kind {
+	case EnvCodespace:
+		return "\uf408"
+	case EnvDocker:
+		return "\uf308"
+	case EnvKubernetes:
+		return "\U000F10FE"
+	case EnvNixShell:
+		return "\uf313"
+	case EnvDirenv:
+		return "\uf07c"
+	default:
+		return "\uf4b7"
+	}
+}
@@ -0,0 +1,89 @@
+package segments
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/LNKLEO/OMP/log"
+	"github.com/LNKLEO/OMP/runtime"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	GCPNOACTIVECONFIG = "NO ACTIVE CONFIG FOUND"
+)
+
+type Gcp struct {
+	base
+
+	ActiveConfig string
+	Account      string
+	Project      string
+	Region       string
+	Zone         string
+}
+
+func (g *Gcp) Template() string {
+	return " {{ .Project }} :: {{ .Account }} "
+}
+
+func (g *Gcp) Enabled() bool {
+	cfgDir := g.getConfigDirectory()
+
+	activeConfig, err := g.getActiveConfig(cfgDir)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	g.ActiveConfig = activeConfig
+
+	cfgPath := filepath.Join(cfgDir, "configurations", "config_"+activeConfig)
+	content := g.env.FileContent(cfgPath)
+	if len(content) == 0 {
+		log.Debugf("no gcloud configuration found at %s", cfgPath)
+		return false
+	}
+
+	cfg, err := ini.Load([]byte(content))
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	g.Project = cfg.Section("core").Key("project").String()
+	g.Account = cfg.Section("core").Key("account").String()
+	g.Region = cfg.Section("compute").Key("region").String()
+	g.Zone = cfg.Section("compute").Key("zone").String()
+
+	return true
+}
+
+func (g *Gcp) getActiveConfig(cfgDir string) (string, error) {
+	// the environment variable takes precedence over the active_config file
+	if activeConfig := g.env.Getenv("CLOUDSDK_ACTIVE_CONFIG_NAME"); len(activeConfig) != 0 {
+		return activeConfig, nil
+	}
+
+	content := g.env.FileContent(filepath.Join(cfgDir, "active_config"))
+	activeConfig := strings.TrimSpace(content)
+	if len(activeConfig) == 0 {
+		return "", errors.New(GCPNOACTIVECONFIG)
+	}
+
+	return activeConfig, nil
+}
+
+func (g *Gcp) getConfigDirectory() string {
+	if cfgDir := g.env.Getenv("CLOUDSDK_CONFIG"); len(cfgDir) != 0 {
+		return cfgDir
+	}
+
+	if g.env.GOOS() == runtime.WINDOWS {
+		return filepath.Join(g.env.Getenv("APPDATA"), "gcloud")
+	}
+
+	return filepath.Join(g.env.Home(), ".config", "gcloud")
+}
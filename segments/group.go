@@ -0,0 +1,27 @@
+package segments
+
+type Group struct {
+	base
+
+	// Children contains the data of the enabled child segments, by their unique alias or type
+	Children map[string]any
+	// Texts contains the rendered text of the enabled child segments, in order
+	Texts []string
+}
+
+func (g *Group) Template() string {
+	return "{{ range .Texts }}{{ . }}{{ end }}"
+}
+
+func (g *Group) Enabled() bool {
+	return len(g.Texts) != 0
+}
+
+func (g *Group) AddChild(name string, writer any, text string) {
+	if g.Children == nil {
+		g.Children = make(map[string]any)
+	}
+
+	g.Children[name] = writer
+	g.Texts = append(g.Texts, text)
+}
@@ -0,0 +1,61 @@
+package segments
+
+import (
+	"github.com/LNKLEO/OMP/log"
+	"github.com/LNKLEO/OMP/properties"
+
+	yaml "github.com/goccy/go-yaml"
+)
+
+type Helm struct {
+	base
+
+	helmChart
+	ChartPath string
+}
+
+type helmChart struct {
+	APIVersion  string `yaml:"apiVersion"`
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	AppVersion  string `yaml:"appVersion"`
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+}
+
+func (h *Helm) Template() string {
+	return " \U000F0833 {{ .Name }}{{ if .Version }} {{ .Version }}{{ end }} "
+}
+
+func (h *Helm) Enabled() bool {
+	files := h.props.GetStringArray(properties.Files, []string{"Chart.yaml", "Chart.yml"})
+
+	for _, file := range files {
+		chart, err := h.env.HasParentFilePath(file, false)
+		if err != nil {
+			continue
+		}
+
+		return h.parseChart(chart.Path)
+	}
+
+	return false
+}
+
+func (h *Helm) parseChart(file string) bool {
+	content := h.env.FileContent(file)
+	if len(content) == 0 {
+		return false
+	}
+
+	var chart helmChart
+	if err := yaml.Unmarshal([]byte(content), &chart); err != nil {
+		log.Error(err)
+		return false
+	}
+
+	h.helmChart = chart
+	h.ChartPath = file
+
+	return true
+}
@@ -0,0 +1,140 @@
+package segments
+
+import (
+	"path/filepath"
+
+	"github.com/LNKLEO/OMP/log"
+	"github.com/LNKLEO/OMP/properties"
+	"github.com/LNKLEO/OMP/regex"
+
+	yaml "github.com/goccy/go-yaml"
+)
+
+const (
+	// ProductionPattern is the regex a context name is matched against to mark it as production
+	ProductionPattern properties.Property = "production_pattern"
+	// ContextAliases allows to map a context name to a shorter or more readable one
+	ContextAliases properties.Property = "context_aliases"
+)
+
+type Kubectl struct {
+	base
+
+	KubeContext
+	Context    string
+	Production bool
+}
+
+type KubeConfig struct {
+	CurrentContext string          `yaml:"current-context"`
+	Contexts       []*KubeContexts `yaml:"contexts"`
+}
+
+type KubeContexts struct {
+	Context *KubeContext `yaml:"context"`
+	Name    string       `yaml:"name"`
+}
+
+type KubeContext struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user"`
+	Namespace string `yaml:"namespace"`
+}
+
+func (k *Kubectl) Template() string {
+	return " \U000F10FE {{ .Context }}{{ if .Namespace }} :: {{ .Namespace }}{{ end }} "
+}
+
+func (k *Kubectl) Enabled() bool {
+	if !k.parseKubeConfig() {
+		return false
+	}
+
+	// match against the real context name, aliases are for display only
+	pattern := k.props.GetString(ProductionPattern, `(?i)prod`)
+	if len(pattern) != 0 {
+		k.Production = regex.MatchString(pattern, k.Context)
+	}
+
+	k.setContextAlias()
+
+	return true
+}
+
+func (k *Kubectl) configFiles() []string {
+	kubeconfig := k.env.Getenv("KUBECONFIG")
+	if len(kubeconfig) == 0 {
+		return []string{filepath.Join(k.env.Home(), ".kube", "config")}
+	}
+
+	return filepath.SplitList(kubeconfig)
+}
+
+// parseKubeConfig merges the kubeconfig files the same way kubectl does:
+// the first file to set a value wins, contexts are merged by name.
+func (k *Kubectl) parseKubeConfig() bool {
+	var currentContext string
+	contexts := make(map[string]*KubeContext)
+
+	for _, file := range k.configFiles() {
+		if len(file) == 0 {
+			continue
+		}
+
+		content := k.env.FileContent(file)
+		if len(content) == 0 {
+			continue
+		}
+
+		var config KubeConfig
+		if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+			log.Error(err)
+			continue
+		}
+
+		for _, context := range config.Contexts {
+			if context == nil || len(context.Name) == 0 {
+				continue
+			}
+
+			if _, exists := contexts[context.Name]; exists {
+				continue
+			}
+
+			contexts[context.Name] = context.Context
+		}
+
+		if len(currentContext) == 0 {
+			currentContext = config.CurrentContext
+		}
+	}
+
+	if len(currentContext) == 0 {
+		log.Debug("no current kubernetes context found")
+		return false
+	}
+
+	k.Context = currentContext
+
+	context, exists := contexts[currentContext]
+	if !exists || context == nil {
+		// the current context points to a context that doesn't exist (anymore),
+		// we still want to show it so the user knows something is off
+		return true
+	}
+
+	k.KubeContext = *context
+
+	if len(k.Namespace) == 0 {
+		k.Namespace = "default"
+	}
+
+	return true
+}
+
+func (k *Kubectl) setContextAlias() {
+	aliases := k.props.GetKeyValueMap(ContextAliases, map[string]string{})
+	if alias, exists := aliases[k.Context]; exists {
+		k.Context = alias
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 type Node struct {
 	PackageManagerIcon string
+	NodeWorkspace
 	language
 }
 
@@ -41,7 +42,15 @@ func (n *Node) Enabled() bool {
 	n.language.matchesVersionFile = n.matchesVersionFile
 	n.language.loadContext = n.loadContext
 
-	return n.language.Enabled()
+	if !n.language.Enabled() {
+		return false
+	}
+
+	if n.props.GetBool(FetchPackageWorkspace, false) {
+		n.NodeWorkspace, _ = n.language.nodeWorkspace()
+	}
+
+	return true
 }
 
 func (n *Node) loadContext() {
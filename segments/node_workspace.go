@@ -0,0 +1,116 @@
+package segments
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/LNKLEO/OMP/properties"
+)
+
+const (
+	// FetchPackageWorkspace fetches the monorepo (pnpm, npm/yarn workspaces, nx or turbo) the current package belongs to
+	FetchPackageWorkspace properties.Property = "fetch_package_workspace"
+
+	PnpmWorkspace  = "pnpm"
+	NodeWorkspaces = "workspaces"
+	NxWorkspace    = "nx"
+	TurboWorkspace = "turbo"
+)
+
+// NodeWorkspace describes the monorepo the current folder belongs to.
+type NodeWorkspace struct {
+	// Workspace is the root folder of the monorepo
+	Workspace     string
+	WorkspaceType string
+	// Package is the name of the package we're currently in
+	Package string
+	// PackagePath is the folder of the current package, relative to Workspace
+	PackagePath string
+}
+
+type nodePackageJSON struct {
+	Workspaces any    `json:"workspaces"`
+	Name       string `json:"name"`
+}
+
+func (l *language) nodeWorkspace() (NodeWorkspace, bool) {
+	var workspace NodeWorkspace
+
+	root, workspaceType := l.findNodeWorkspaceRoot()
+	if len(root) == 0 {
+		return workspace, false
+	}
+
+	workspace.Workspace = root
+	workspace.WorkspaceType = workspaceType
+
+	packageFile, err := l.env.HasParentFilePath("package.json", false)
+	if err != nil {
+		return workspace, true
+	}
+
+	relative, err := filepath.Rel(root, packageFile.ParentFolder)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		// the closest package.json lives above the workspace root
+		return workspace, true
+	}
+
+	workspace.PackagePath = filepath.ToSlash(relative)
+
+	if data, ok := l.readPackageJSON(packageFile.Path); ok {
+		workspace.Package = data.Name
+	}
+
+	return workspace, true
+}
+
+// findNodeWorkspaceRoot walks up from the current folder and returns the first
+// folder containing a workspace definition.
+func (l *language) findNodeWorkspaceRoot() (string, string) {
+	markers := []struct {
+		file          string
+		workspaceType string
+	}{
+		{file: "pnpm-workspace.yaml", workspaceType: PnpmWorkspace},
+		{file: "nx.json", workspaceType: NxWorkspace},
+		{file: "turbo.json", workspaceType: TurboWorkspace},
+	}
+
+	dir := l.env.Pwd()
+
+	for {
+		for _, marker := range markers {
+			if l.env.HasFilesInDir(dir, marker.file) {
+				return dir, marker.workspaceType
+			}
+		}
+
+		if l.env.HasFilesInDir(dir, "package.json") {
+			if data, ok := l.readPackageJSON(filepath.Join(dir, "package.json")); ok && data.Workspaces != nil {
+				return dir, NodeWorkspaces
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+
+		dir = parent
+	}
+}
+
+func (l *language) readPackageJSON(file string) (*nodePackageJSON, bool) {
+	content := l.env.FileContent(file)
+	if len(content) == 0 {
+		return nil, false
+	}
+
+	var data nodePackageJSON
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return nil, false
+	}
+
+	return &data, true
+}
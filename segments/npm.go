@@ -1,6 +1,7 @@
 package segments
 
 type Npm struct {
+	NodeWorkspace
 	language
 }
 
@@ -15,7 +16,15 @@ func (n *Npm) Enabled() bool {
 	}
 	n.versionURLTemplate = "https://github.com/npm/cli/releases/tag/v{{ .Full }}"
 
-	return n.language.Enabled()
+	if !n.language.Enabled() {
+		return false
+	}
+
+	if n.props.GetBool(FetchPackageWorkspace, false) {
+		n.NodeWorkspace, _ = n.language.nodeWorkspace()
+	}
+
+	return true
 }
 
 func (n *Npm) Template() string {
@@ -2,6 +2,9 @@ package segments
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,12 +16,21 @@ import (
 	"github.com/LNKLEO/OMP/runtime/path"
 	"github.com/LNKLEO/OMP/shell"
 	"github.com/LNKLEO/OMP/template"
+
+	"github.com/mattn/go-runewidth"
 )
 
 type Folder struct {
 	Name    string
 	Path    string
 	Display bool
+	// location is the folder on disk, Path has the mapped locations replaced
+	location string
+}
+
+// as returns a copy of the folder displayed as name, like a collapsed folder icon
+func (f *Folder) as(name string) *Folder {
+	return &Folder{Name: name, Path: f.Path, Display: f.Display, location: f.location}
 }
 
 type Folders []*Folder
@@ -40,15 +52,25 @@ type Path struct {
 	root            string
 	relative        string
 	pwd             string
+	rootLocation    string
 	Location        string
 	pathSeparator   string
 	Path            string
+	ProjectRoot     string
+	ProjectRelative string
+	MountType       string
+	MountPoint      string
+	SymlinkTarget   string
 	Folders         Folders
 	StackCount      int
+	FreeSpace       float64
 	windowsPath     bool
 	Writable        bool
 	RootDir         bool
 	cygPath         bool
+	NetworkMount    bool
+	GitIgnored      bool
+	Symlink         bool
 }
 
 const (
@@ -85,6 +107,8 @@ const (
 	// Powerlevel tries to mimic the powerlevel10k path,
 	// used in combination with max_width.
 	Powerlevel string = "powerlevel"
+	// ProjectType anchors the path at the closest project root and displays the path relative to it
+	ProjectType string = "project"
 	// MixedThreshold the threshold of the length of the path Mixed will display
 	MixedThreshold properties.Property = "mixed_threshold"
 	// MappedLocations allows overriding certain location with an icon
@@ -93,7 +117,7 @@ const (
 	MappedLocationsEnabled properties.Property = "mapped_locations_enabled"
 	// MaxDepth Maximum path depth to display whithout shortening
 	MaxDepth properties.Property = "max_depth"
-	// MaxWidth Maximum path width to display for powerlevel style
+	// MaxWidth Maximum path width to display for powerlevel style, or any style when fit_to_width is enabled
 	MaxWidth properties.Property = "max_width"
 	// Hides the root location if it doesn't fit in max_depth. Used in Agnoster Short
 	HideRootLocation properties.Property = "hide_root_location"
@@ -113,6 +137,22 @@ const (
 	GitDirFormat properties.Property = "gitdir_format"
 	// DisplayCygpath transforms the path to a cygpath format
 	DisplayCygpath properties.Property = "display_cygpath"
+	// ProjectMarkers the files or folders that identify a project root
+	ProjectMarkers properties.Property = "project_markers"
+	// ProjectRootFormat format to use on the project root folder
+	ProjectRootFormat properties.Property = "project_root_format"
+	// FitToWidth progressively shortens the path until it fits the available width
+	FitToWidth properties.Property = "fit_to_width"
+	// FolderHyperlinks renders every folder as a hyperlink to its location
+	FolderHyperlinks properties.Property = "folder_hyperlinks"
+	// FetchMountInfo fetches the mount point and file system type of the current folder
+	FetchMountInfo properties.Property = "fetch_mount_info"
+	// FetchFreeSpace fetches the free space percentage of the mount the current folder is on
+	FetchFreeSpace properties.Property = "fetch_free_space"
+	// FetchGitIgnored checks if the current folder is ignored in the enclosing git repository
+	FetchGitIgnored properties.Property = "fetch_git_ignored"
+	// FetchSymlink checks if the current folder is a symlink and resolves its target
+	FetchSymlink properties.Property = "fetch_symlink"
 )
 
 func (pt *Path) Template() string {
@@ -125,6 +165,8 @@ func (pt *Path) Enabled() bool {
 		return false
 	}
 
+	// the project root is available to templates for every style
+	pt.setProjectRoot()
 	pt.setStyle()
 	pwd := pt.env.Pwd()
 
@@ -135,9 +177,106 @@ func (pt *Path) Enabled() bool {
 
 	pt.StackCount = pt.env.StackCount()
 	pt.Writable = pt.env.DirIsWritable(pwd)
+	pt.setMetadata(pwd)
 	return true
 }
 
+func (pt *Path) setMetadata(pwd string) {
+	if pt.props.GetBool(FetchSymlink, false) {
+		if target, err := pt.env.ResolveSymlink(pwd); err == nil && target != pwd {
+			pt.Symlink = true
+			pt.SymlinkTarget = target
+		}
+	}
+
+	if pt.props.GetBool(FetchMountInfo, false) {
+		pt.setMountInfo(pwd)
+	}
+
+	if pt.props.GetBool(FetchFreeSpace, false) {
+		if usage, err := pt.env.DiskUsage(pwd); err == nil && usage.Total != 0 {
+			pt.FreeSpace = float64(usage.Free) / float64(usage.Total) * 100
+		}
+	}
+
+	if pt.props.GetBool(FetchGitIgnored, false) {
+		pt.GitIgnored = pt.isGitIgnored(pwd)
+	}
+}
+
+// setMountInfo finds the mount the folder lives on in /proc/self/mountinfo, only available on Linux.
+func (pt *Path) setMountInfo(pwd string) {
+	if pt.env.GOOS() != runtime.LINUX {
+		return
+	}
+
+	// mount points are listed with symlinks resolved
+	if target, err := pt.env.ResolveSymlink(pwd); err == nil {
+		pwd = target
+	}
+
+	mountInfo := pt.env.FileContent("/proc/self/mountinfo")
+
+	for _, line := range strings.Split(mountInfo, "\n") {
+		// 36 35 98:0 /mnt1 /mnt/parent rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(line)
+		separator := slices.Index(fields, "-")
+		if len(fields) < 5 || separator == -1 || separator+1 >= len(fields) {
+			continue
+		}
+
+		mountPoint := unescapeMountPath(fields[4])
+		if !isSubPath(mountPoint, pwd) || len(mountPoint) < len(pt.MountPoint) {
+			continue
+		}
+
+		pt.MountPoint = mountPoint
+		pt.MountType = fields[separator+1]
+	}
+
+	pt.NetworkMount = isNetworkFileSystem(pt.MountType)
+}
+
+func (pt *Path) isGitIgnored(pwd string) bool {
+	if _, err := pt.env.HasParentFilePath(".git", true); err != nil {
+		return false
+	}
+
+	// git exits with 0 when the path is ignored
+	_, err := pt.env.RunCommand("git", "-C", pwd, "check-ignore", "-q", ".")
+	return err == nil
+}
+
+func isSubPath(parent, child string) bool {
+	if parent == "/" || parent == child {
+		return true
+	}
+
+	return strings.HasPrefix(child, parent+"/")
+}
+
+// unescapeMountPath replaces the octal escapes used for spaces, tabs and newlines in mountinfo
+func unescapeMountPath(input string) string {
+	if !strings.Contains(input, `\`) {
+		return input
+	}
+
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(input)
+}
+
+func isNetworkFileSystem(fsType string) bool {
+	if strings.HasPrefix(fsType, "fuse") {
+		return true
+	}
+
+	switch strings.Split(fsType, ".")[0] {
+	case "nfs", "nfs4", "cifs", "smb3", "smbfs", "9p", "afs", "ceph", "glusterfs", "lustre", "davfs", "sshfs":
+		return true
+	}
+
+	return false
+}
+
 func (pt *Path) setPaths() {
 	defer func() {
 		pt.Folders = pt.splitPath()
@@ -206,7 +345,7 @@ func (pt *Path) setStyle() {
 			root += pt.getFolderSeparator()
 		}
 
-		pt.Path = pt.colorizePath(root, nil)
+		pt.Path = pt.colorizePath(pt.rootFolder().as(root), nil)
 		return
 	}
 
@@ -232,11 +371,76 @@ func (pt *Path) setStyle() {
 	case Powerlevel:
 		maxWidth := pt.getMaxWidth()
 		pt.Path = pt.getUniqueLettersPath(maxWidth)
+	case ProjectType:
+		pt.Path = pt.getProjectPath()
 	default:
 		pt.Path = fmt.Sprintf("Path style: %s is not available", style)
+		return
+	}
+
+	if maxWidth := pt.getMaxWidth(); maxWidth > 0 {
+		pt.fit(maxWidth)
 	}
 }
 
+// Fit shortens the path by the given overflow, used by the engine
+// when the line the path is on doesn't fit the terminal width.
+func (pt *Path) Fit(overflow int) bool {
+	if overflow <= 0 {
+		return false
+	}
+
+	return pt.fit(pt.displayWidth(pt.Path) - overflow)
+}
+
+// fit applies progressively shorter styles until the path fits within width.
+// When none of them fit, the shortest one is used.
+func (pt *Path) fit(width int) bool {
+	if !pt.props.GetBool(FitToWidth, false) || len(pt.relative) == 0 {
+		return false
+	}
+
+	if pt.displayWidth(pt.Path) <= width {
+		return false
+	}
+
+	strategies := []func() string{
+		pt.getFullPath,
+		pt.getMixedPath,
+		func() string { return pt.getUniqueLettersPath(0) },
+		pt.getLetterPath,
+		pt.getFolderPath,
+	}
+
+	current := pt.Path
+
+	for _, strategy := range strategies {
+		path := strategy()
+		if pt.displayWidth(path) >= pt.displayWidth(current) {
+			continue
+		}
+
+		current = path
+		if pt.displayWidth(current) <= width {
+			break
+		}
+	}
+
+	if current == pt.Path {
+		return false
+	}
+
+	pt.Path = current
+	return true
+}
+
+// displayWidth returns the visible width of the text, ignoring hyperlinks and color or style anchors
+func (pt *Path) displayWidth(text string) int {
+	text = regex.ReplaceAllString(`<LINK>[^<]*<TEXT>`, text, "")
+	text = regex.ReplaceAllString(`<[^<>]*>`, text, "")
+	return runewidth.StringWidth(text)
+}
+
 func (pt *Path) getMaxWidth() int {
 	width := pt.props.GetString(MaxWidth, "")
 	if len(width) == 0 {
@@ -293,74 +497,56 @@ func (pt *Path) getFolderSeparator() string {
 }
 
 func (pt *Path) getMixedPath() string {
-	root := pt.root
-	folders := pt.Folders
+	root, folders := pt.splitRoot()
 	threshold := int(pt.props.GetFloat64(MixedThreshold, 4))
 	folderIcon := pt.props.GetString(FolderIcon, "..")
 
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
-
-	var elements []string
+	var elements Folders
 
 	for i, n := 0, len(folders); i < n; i++ {
 		folderName := folders[i].Name
 		if len(folderName) > threshold && i != n-1 && !folders[i].Display {
-			elements = append(elements, folderIcon)
+			elements = append(elements, folders[i].as(folderIcon))
 			continue
 		}
 
-		elements = append(elements, folderName)
+		elements = append(elements, folders[i])
 	}
 
 	return pt.colorizePath(root, elements)
 }
 
 func (pt *Path) getAgnosterPath() string {
-	root := pt.root
-	folders := pt.Folders
+	root, folders := pt.splitRoot()
 	folderIcon := pt.props.GetString(FolderIcon, "..")
 
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
-
-	var elements []string
+	var elements Folders
 
 	for i, n := 0, len(folders); i < n; i++ {
 		if folders[i].Display || i == n-1 {
-			elements = append(elements, folders[i].Name)
+			elements = append(elements, folders[i])
 			continue
 		}
 
-		elements = append(elements, folderIcon)
+		elements = append(elements, folders[i].as(folderIcon))
 	}
 
 	return pt.colorizePath(root, elements)
 }
 
 func (pt *Path) getAgnosterLeftPath() string {
-	root := pt.root
-	folders := pt.Folders
+	root, folders := pt.splitRoot()
 	folderIcon := pt.props.GetString(FolderIcon, "..")
 
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
-
-	var elements []string
-	elements = append(elements, folders[0].Name)
+	var elements Folders
+	elements = append(elements, folders[0])
 	for i, n := 1, len(folders); i < n; i++ {
 		if folders[i].Display {
-			elements = append(elements, folders[i].Name)
+			elements = append(elements, folders[i])
 			continue
 		}
 
-		elements = append(elements, folderIcon)
+		elements = append(elements, folders[i].as(folderIcon))
 	}
 
 	return pt.colorizePath(root, elements)
@@ -384,40 +570,27 @@ func (pt *Path) getRelevantLetter(folder *Folder) string {
 }
 
 func (pt *Path) getLetterPath() string {
-	root := pt.root
-	folders := pt.Folders
-
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
-
-	root = pt.getRelevantLetter(&Folder{Name: root})
+	root, folders := pt.splitRoot()
+	root = root.as(pt.getRelevantLetter(&Folder{Name: root.Name}))
 
-	var elements []string
+	var elements Folders
 	for i, n := 0, len(folders); i < n; i++ {
 		if folders[i].Display || i == n-1 {
-			elements = append(elements, folders[i].Name)
+			elements = append(elements, folders[i])
 			continue
 		}
 
 		letter := pt.getRelevantLetter(folders[i])
-		elements = append(elements, letter)
+		elements = append(elements, folders[i].as(letter))
 	}
 
 	return pt.colorizePath(root, elements)
 }
 
 func (pt *Path) getUniqueLettersPath(maxWidth int) string {
-	root := pt.root
-	folders := pt.Folders
+	root, folders := pt.splitRoot()
 	separator := pt.getFolderSeparator()
 
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
-
 	folderNames := folders.List()
 
 	usePowerlevelStyle := func(root, relative string) bool {
@@ -430,22 +603,22 @@ func (pt *Path) getUniqueLettersPath(maxWidth int) string {
 
 	if maxWidth > 0 {
 		relative := strings.Join(folderNames, separator)
-		if usePowerlevelStyle(root, relative) {
-			return pt.colorizePath(root, folderNames)
+		if usePowerlevelStyle(root.Name, relative) {
+			return pt.colorizePath(root, folders)
 		}
 	}
 
-	root = pt.getRelevantLetter(&Folder{Name: root})
+	root = root.as(pt.getRelevantLetter(&Folder{Name: root.Name}))
 
-	var elements []string
+	var elements Folders
 	letters := make(map[string]bool)
-	letters[root] = true
+	letters[root.Name] = true
 
 	for i, n := 0, len(folders); i < n; i++ {
 		folderName := folderNames[i]
 
 		if i == n-1 {
-			elements = append(elements, folderName)
+			elements = append(elements, folders[i])
 			break
 		}
 
@@ -459,15 +632,15 @@ func (pt *Path) getUniqueLettersPath(maxWidth int) string {
 		}
 
 		letters[letter] = true
-		elements = append(elements, letter)
+		elements = append(elements, folders[i].as(letter))
 
 		// only return early on maxWidth > 0
 		// this enables the powerlevel10k behavior
 		if maxWidth > 0 {
-			list := elements
-			list = append(list, folderNames[i+1:]...)
-			relative := strings.Join(list, separator)
-			if usePowerlevelStyle(root, relative) {
+			list := slices.Clone(elements)
+			list = append(list, folders[i+1:]...)
+			relative := strings.Join(list.List(), separator)
+			if usePowerlevelStyle(root.Name, relative) {
 				return pt.colorizePath(root, list)
 			}
 		}
@@ -477,25 +650,12 @@ func (pt *Path) getUniqueLettersPath(maxWidth int) string {
 }
 
 func (pt *Path) getAgnosterFullPath() string {
-	root := pt.root
-	folders := pt.Folders
-
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
-
-	return pt.colorizePath(root, folders.List())
+	root, folders := pt.splitRoot()
+	return pt.colorizePath(root, folders)
 }
 
 func (pt *Path) getAgnosterShortPath() string {
-	root := pt.root
-	folders := pt.Folders
-
-	if pt.isRootFS(root) {
-		root = folders[0].Name
-		folders = folders[1:]
-	}
+	root, folders := pt.splitRoot()
 
 	maxDepth := pt.props.GetInt(MaxDepth, 1)
 	if maxDepth < 1 {
@@ -511,10 +671,14 @@ func (pt *Path) getAgnosterShortPath() string {
 		return pt.getAgnosterFullPath()
 	}
 
-	elements := []string{folderIcon}
+	// the icon stands for the hidden folders, it links to the last one
+	elements := Folders{root.as(folderIcon)}
+	if hidden := pathDepth - maxDepth - 1; hidden >= 0 {
+		elements[0] = folders[hidden].as(folderIcon)
+	}
 
 	for i := pathDepth - maxDepth; i < pathDepth; i++ {
-		elements = append(elements, folders[i].Name)
+		elements = append(elements, folders[i])
 	}
 
 	if hideRootLocation {
@@ -525,12 +689,99 @@ func (pt *Path) getAgnosterShortPath() string {
 }
 
 func (pt *Path) getFullPath() string {
-	return pt.colorizePath(pt.root, pt.Folders.List())
+	return pt.colorizePath(pt.rootFolder(), pt.Folders)
 }
 
 func (pt *Path) getFolderPath() string {
-	folderName := pt.Folders[len(pt.Folders)-1].Name
-	return pt.colorizePath(folderName, nil)
+	return pt.colorizePath(pt.Folders[len(pt.Folders)-1], nil)
+}
+
+// rootFolder returns the root location of the path, like ~ or /
+func (pt *Path) rootFolder() *Folder {
+	return &Folder{Name: pt.root, Path: pt.root, location: pt.rootLocation}
+}
+
+// splitRoot returns the root and the folders below it. The folder
+// at the root of the file system is used as the root when there is one.
+func (pt *Path) splitRoot() (*Folder, Folders) {
+	if pt.isRootFS(pt.root) {
+		return pt.Folders[0], pt.Folders[1:]
+	}
+
+	return pt.rootFolder(), pt.Folders
+}
+
+func (pt *Path) getProjectPath() string {
+	if len(pt.ProjectRoot) == 0 {
+		return pt.getAgnosterPath()
+	}
+
+	var depth int
+	if len(pt.ProjectRelative) != 0 {
+		depth = len(strings.Split(filepath.ToSlash(pt.ProjectRelative), "/"))
+	}
+
+	// mapped locations can hide the project root, fall back to the default style
+	folders := pt.Folders
+	if len(folders) < depth {
+		return pt.getAgnosterPath()
+	}
+
+	rootFormat := pt.props.GetString(ProjectRootFormat, "<b>%s</b>")
+	folderIcon := pt.props.GetString(FolderIcon, "..")
+	index := len(folders) - depth - 1
+
+	elements := slices.Clone(folders[index+1:])
+
+	// the project root is the (mapped) root location itself
+	if index < 0 {
+		root := pt.rootFolder()
+		return pt.colorizePath(root.as(fmt.Sprintf(rootFormat, root.Name)), elements)
+	}
+
+	elements = append(Folders{folders[index].as(fmt.Sprintf(rootFormat, folders[index].Name))}, elements...)
+
+	// only collapse when there's more than the root location above the project root
+	root := pt.rootFolder()
+	if index > 0 {
+		root = folders[index-1].as(folderIcon)
+	}
+
+	return pt.colorizePath(root, elements)
+}
+
+// setProjectRoot finds the closest parent folder containing one of the project markers.
+func (pt *Path) setProjectRoot() {
+	markers := pt.props.GetStringArray(ProjectMarkers, []string{".git", "go.mod", "package.json"})
+	pwd := pt.env.Pwd()
+
+	var projectRoot string
+	for _, marker := range markers {
+		file, err := pt.env.HasParentFilePath(marker, false)
+		if err != nil {
+			continue
+		}
+
+		// the closest project root wins
+		if len(file.ParentFolder) > len(projectRoot) {
+			projectRoot = file.ParentFolder
+		}
+	}
+
+	if len(projectRoot) == 0 {
+		return
+	}
+
+	relative, err := filepath.Rel(projectRoot, pwd)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	pt.ProjectRoot = projectRoot
+	if relative != "." {
+		pt.ProjectRelative = relative
+	}
 }
 
 func (pt *Path) join(root, relative string) string {
@@ -730,7 +981,7 @@ func (pt *Path) normalize(inputPath string) string {
 	return normalized
 }
 
-func (pt *Path) colorizePath(root string, elements []string) string {
+func (pt *Path) colorizePath(root *Folder, elements Folders) string {
 	cycle := pt.props.GetStringArray(Cycle, []string{})
 	skipColorize := len(cycle) == 0
 	folderSeparator := pt.getFolderSeparator()
@@ -741,6 +992,15 @@ func (pt *Path) colorizePath(root string, elements []string) string {
 	leftFormat := pt.props.GetString(LeftFormat, edgeFormat)
 	rightFormat := pt.props.GetString(RightFormat, edgeFormat)
 
+	folderHyperlinks := pt.props.GetBool(FolderHyperlinks, false)
+	linkElement := func(element string, folder *Folder) string {
+		if !folderHyperlinks || len(element) == 0 || len(folder.location) == 0 {
+			return element
+		}
+
+		return fmt.Sprintf("<LINK>%s<TEXT>%s</TEXT></LINK>", pt.folderURL(folder.location), element)
+	}
+
 	colorizeElement := func(element string) string {
 		if skipColorize || len(element) == 0 {
 			return element
@@ -752,8 +1012,8 @@ func (pt *Path) colorizePath(root string, elements []string) string {
 	}
 
 	if len(elements) == 0 {
-		formattedRoot := fmt.Sprintf(leftFormat, root)
-		return colorizeElement(formattedRoot)
+		formattedRoot := fmt.Sprintf(leftFormat, root.Name)
+		return colorizeElement(linkElement(formattedRoot, root))
 	}
 
 	colorizeSeparator := func() string {
@@ -765,15 +1025,15 @@ func (pt *Path) colorizePath(root string, elements []string) string {
 
 	sb := new(strings.Builder)
 
-	formattedRoot := fmt.Sprintf(leftFormat, root)
-	sb.WriteString(colorizeElement(formattedRoot))
+	formattedRoot := fmt.Sprintf(leftFormat, root.Name)
+	sb.WriteString(colorizeElement(linkElement(formattedRoot, root)))
 
-	if !pt.endWithSeparator(root) {
+	if !pt.endWithSeparator(root.Name) {
 		sb.WriteString(colorizeSeparator())
 	}
 
-	for i, element := range elements {
-		if len(element) == 0 {
+	for i, folder := range elements {
+		if len(folder.Name) == 0 {
 			continue
 		}
 
@@ -782,8 +1042,8 @@ func (pt *Path) colorizePath(root string, elements []string) string {
 			format = rightFormat
 		}
 
-		formattedElement := fmt.Sprintf(format, element)
-		sb.WriteString(colorizeElement(formattedElement))
+		formattedElement := fmt.Sprintf(format, folder.Name)
+		sb.WriteString(colorizeElement(linkElement(formattedElement, folder)))
 		if i != len(elements)-1 {
 			sb.WriteString(colorizeSeparator())
 		}
@@ -792,10 +1052,33 @@ func (pt *Path) colorizePath(root string, elements []string) string {
 	return sb.String()
 }
 
+// folderURL returns the file URL of the folder, including the hostname
+// when in a remote session so the terminal can tell them apart.
+func (pt *Path) folderURL(location string) string {
+	location = filepath.ToSlash(location)
+	if !strings.HasPrefix(location, "/") {
+		location = "/" + location
+	}
+
+	var host string
+	if len(pt.env.Getenv("SSH_CONNECTION")) != 0 || len(pt.env.Getenv("SSH_CLIENT")) != 0 {
+		host, _ = pt.env.Host()
+	}
+
+	fileURL := &url.URL{
+		Scheme: "file",
+		Host:   host,
+		Path:   location,
+	}
+
+	return fileURL.String()
+}
+
 func (pt *Path) splitPath() Folders {
 	folders := Folders{}
 
 	if len(pt.relative) == 0 {
+		pt.rootLocation = pt.env.Pwd()
 		return folders
 	}
 
@@ -824,6 +1107,15 @@ func (pt *Path) splitPath() Folders {
 		display = false
 	}
 
+	// mapped locations only replace the start of the path, the folders are the trailing ones on disk
+	location := pt.env.Pwd()
+	for i := len(folders) - 1; i >= 0; i-- {
+		folders[i].location = location
+		location = filepath.Dir(location)
+	}
+
+	pt.rootLocation = location
+
 	return folders
 }
 
@@ -0,0 +1,137 @@
+package segments
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/LNKLEO/OMP/properties"
+	"github.com/LNKLEO/OMP/regex"
+)
+
+const (
+	// FetchWorkspace fetches the active terraform workspace
+	FetchWorkspace properties.Property = "fetch_workspace"
+	// FetchRequiredVersion parses the required_version constraint from the *.tf files
+	FetchRequiredVersion properties.Property = "fetch_required_version"
+	// FetchBackend fetches the configured backend type from the local state
+	FetchBackend properties.Property = "fetch_backend"
+
+	terraformFolder = ".terraform"
+)
+
+type Terraform struct {
+	language
+
+	WorkspaceName   string
+	RequiredVersion string
+	Backend         string
+}
+
+type terraformState struct {
+	Backend struct {
+		Type string `json:"type"`
+	} `json:"backend"`
+}
+
+func (tf *Terraform) Template() string {
+	return " \U000F1062 {{ .WorkspaceName }}{{ if .Full }} {{ .Full }}{{ end }} "
+}
+
+func (tf *Terraform) Enabled() bool {
+	tf.extensions = []string{"*.tf", "*.tofu", "*.tfvars", ".terraform.lock.hcl"}
+	tf.folders = []string{terraformFolder}
+	tf.commands = []*cmd{
+		{
+			executable:         "terraform",
+			args:               []string{"version"},
+			regex:              `Terraform v(?P<version>((?P<major>[0-9]+).(?P<minor>[0-9]+).(?P<patch>[0-9]+))(-(?P<prerelease>[a-z0-9]+))?)`,
+			versionURLTemplate: "https://github.com/hashicorp/terraform/releases/tag/v{{ .Full }}",
+		},
+		{
+			executable:         "tofu",
+			args:               []string{"version"},
+			regex:              `OpenTofu v(?P<version>((?P<major>[0-9]+).(?P<minor>[0-9]+).(?P<patch>[0-9]+))(-(?P<prerelease>[a-z0-9]+))?)`,
+			versionURLTemplate: "https://github.com/opentofu/opentofu/releases/tag/v{{ .Full }}",
+		},
+	}
+
+	if !tf.language.Enabled() {
+		return false
+	}
+
+	// only read the configuration and state once the segment is enabled, the language
+	// context hook runs before that in every directory
+	tf.fetchContext()
+
+	return true
+}
+
+func (tf *Terraform) fetchContext() {
+	if tf.props.GetBool(FetchWorkspace, true) {
+		tf.WorkspaceName = tf.workspace()
+	}
+
+	if tf.props.GetBool(FetchRequiredVersion, true) {
+		tf.RequiredVersion = tf.requiredVersion()
+	}
+
+	if tf.props.GetBool(FetchBackend, true) {
+		tf.Backend = tf.backend()
+	}
+}
+
+func (tf *Terraform) workspace() string {
+	// TF_WORKSPACE overrides whatever is selected in the working directory
+	if workspace := tf.env.Getenv("TF_WORKSPACE"); len(workspace) != 0 {
+		return workspace
+	}
+
+	environment := tf.env.FileContent(filepath.Join(terraformFolder, "environment"))
+	if workspace := strings.TrimSpace(environment); len(workspace) != 0 {
+		return workspace
+	}
+
+	return "default"
+}
+
+// requiredVersion looks for the first required_version constraint in the
+// configuration files, which lives inside the terraform block.
+func (tf *Terraform) requiredVersion() string {
+	for _, file := range tf.env.LsDir(tf.env.Pwd()) {
+		if file.IsDir() {
+			continue
+		}
+
+		extension := filepath.Ext(file.Name())
+		if extension != ".tf" && extension != ".tofu" {
+			continue
+		}
+
+		content := tf.env.FileContent(file.Name())
+		if !strings.Contains(content, "required_version") {
+			continue
+		}
+
+		values := regex.FindNamedRegexMatch(`(?m)^\s*required_version\s*=\s*"(?P<constraint>[^"]+)"`, content)
+		if constraint, OK := values["constraint"]; OK && len(constraint) != 0 {
+			return constraint
+		}
+	}
+
+	return ""
+}
+
+func (tf *Terraform) backend() string {
+	content := tf.env.FileContent(filepath.Join(terraformFolder, "terraform.tfstate"))
+	if len(content) == 0 {
+		return ""
+	}
+
+	var state terraformState
+	if err := json.Unmarshal([]byte(content), &state); err != nil {
+		return ""
+	}
+
+	return state.Backend.Type
+}
@@ -15,7 +15,9 @@ func (f Feature) Bash() Code {
 		return unixCursorPositioning
 	case FTCSMarks:
 		return unixFTCSMarks
-	case RPrompt, PoshGit, Azure, LineError, Jobs, Tooltips, Transient:
+	case RPrompt:
+		return bashRPrompt
+	case PoshGit, Azure, LineError, Jobs, Tooltips, Transient:
 		fallthrough
 	default:
 		return ""
@@ -7,6 +7,7 @@ type Code string
 const (
 	unixFTCSMarks         Code = "_omp_ftcs_marks=1"
 	unixCursorPositioning Code = "_omp_cursor_positioning=1"
+	bashRPrompt           Code = "_omp_rprompt=1"
 )
 
 func (c Code) Indent(spaces int) Code {
@@ -22,6 +22,7 @@ _omp_executable=::OMP::
 # switches to enable/disable features
 _omp_cursor_positioning=0
 _omp_ftcs_marks=0
+_omp_rprompt=0
 
 # start timer on command start
 PS0='${_omp_start_time:0:$((_omp_start_time="$(_omp_start_timer)",0))}$(_omp_ftcs_command_start)'
@@ -129,6 +130,11 @@ function _omp_hook() {
         _omp_pipestatus=("$_omp_status")
     fi
 
+    # keep $COLUMNS up to date so the right prompt fits the terminal after a resize
+    if [[ $_omp_rprompt == 1 ]]; then
+        shopt -s checkwinsize
+    fi
+
     set_poshcontext
     _omp_set_cursor_position
 
@@ -68,6 +68,9 @@ func loadCache(vars maps.Simple) {
 		Cache.OS = env.Platform()
 	}
 
+	Cache.TerminalBackground = env.TerminalBackground()
+	Cache.IsDarkBackground = runtime.IsDarkColor(Cache.TerminalBackground)
+
 	val := env.Getenv("SHLVL")
 	if shlvl, err := strconv.Atoi(val); err == nil {
 		Cache.SHLVL = shlvl
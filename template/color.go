@@ -0,0 +1,19 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// colorFunction writes a derived color, like `darken(p:blue, 20)`, which
+// is evaluated once the color is resolved against the palette.
+func colorFunction(name string) func(args ...any) string {
+	return func(args ...any) string {
+		values := make([]string, 0, len(args))
+		for _, arg := range args {
+			values = append(values, fmt.Sprint(arg))
+		}
+
+		return fmt.Sprintf("%s(%s)", name, strings.Join(values, ", "))
+	}
+}
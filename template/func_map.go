@@ -25,6 +25,12 @@ func funcMap() template.FuncMap {
 		"stat":         stat,
 		"dir":          filepath.Dir,
 		"base":         filepath.Base,
+		"darken":       colorFunction("darken"),
+		"lighten":      colorFunction("lighten"),
+		"mix":          colorFunction("mix"),
+		"alphaBlend":   colorFunction("alpha-blend"),
+		"blend":        colorFunction("blend"),
+		"contrast":     colorFunction("contrast"),
 	}
 
 	for key, fun := range sprig.TxtFuncMap() {
@@ -52,6 +52,9 @@ func Init(environment runtime.Environment, vars maps.Simple) {
 		"Var",
 		"Data",
 		"Jobs",
+		"TerminalBackground",
+		"IsDarkBackground",
+		"PaletteName",
 	}
 
 	if Cache != nil {
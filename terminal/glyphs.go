@@ -0,0 +1,246 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/LNKLEO/OMP/log"
+)
+
+// GlyphSet is the set of glyphs the terminal's font can display
+type GlyphSet string
+
+const (
+	// AutoGlyphs detects the glyph set from the environment
+	AutoGlyphs GlyphSet = "auto"
+	// NerdGlyphs writes the Nerd Font icons as is
+	NerdGlyphs GlyphSet = "nerd"
+	// UnicodeGlyphs replaces the Nerd Font icons with standard Unicode symbols
+	UnicodeGlyphs GlyphSet = "unicode"
+	// ASCIIGlyphs replaces the Nerd Font icons and common symbols with plain ASCII
+	ASCIIGlyphs GlyphSet = "ascii"
+)
+
+// Glyphs is the glyph set used to write the prompt
+var Glyphs = NerdGlyphs
+
+type glyph struct {
+	unicode string
+	ascii   string
+}
+
+// the Nerd Font icons used by the default templates of the segments, and the most common ones in themes.
+// Icons not in this list are left out when the font can't display them, rather than showing a box.
+var nerdGlyphs = map[rune]glyph{
+	// powerline
+	'\ue0a0': {"⎇", ""},
+	'\ue0b0': {"▶", ">"},
+	'\ue0b1': {"❯", ">"},
+	'\ue0b2': {"◀", "<"},
+	'\ue0b3': {"❮", "<"},
+	'\ue0b4': {"◗", ")"},
+	'\ue0b5': {")", ")"},
+	'\ue0b6': {"◖", "("},
+	'\ue0b7': {"(", "("},
+	'\ue0b8': {"◣", "\\"},
+	'\ue0b9': {"\\", "\\"},
+	'\ue0ba': {"◢", "/"},
+	'\ue0bb': {"/", "/"},
+	'\ue0bc': {"◤", "/"},
+	'\ue0bd': {"/", "/"},
+	'\ue0be': {"◥", "\\"},
+	'\ue0bf': {"\\", "\\"},
+	// status
+	'\uf00c': {"✓", "ok"},
+	'\uf00d': {"✗", "x"},
+	'\uf05a': {"ℹ", "i"},
+	'\uf071': {"⚠", "!"},
+	'\uf0e7': {"⚡", "#"},
+	'\uf017': {"◷", ""},
+	'\uf015': {"⌂", "~"},
+	'\uf013': {"⚙", "reg"},
+	'\ueba9': {"⇄", "<>"},
+	'\uf1eb': {"wifi", "wifi"},
+	// files and folders
+	'\uea83': {"▸", ""},
+	'\uf07b': {"▸", ""},
+	'\uf07c': {"▸", ""},
+	'\uf120': {">_", ">_"},
+	'\uf489': {">_", ">_"},
+	'\ue795': {">_", ">_"},
+	// git
+	'\ue725': {"⎇", ""},
+	'\uf418': {"⎇", ""},
+	'\uf044': {"✎", "~"},
+	'\uf046': {"✚", "+"},
+	'\uf412': {"⚑", "#"},
+	'\uf417': {"◉", "@"},
+	'\uf594': {"∅", "-"},
+	'\ue727': {"merge", "merge"},
+	'\ue728': {"rebase", "rebase"},
+	'\ue29b': {"pick", "pick"},
+	'\uf0e2': {"revert", "revert"},
+	'\ue5fb': {"git", "git"},
+	'\ue702': {"git", "git"},
+	'\uf1d3': {"git", "git"},
+	'\uf408': {"github", "github"},
+	'\uf296': {"gitlab", "gitlab"},
+	'\uf171': {"bitbucket", "bitbucket"},
+	'\uebe8': {"azure", "azure"},
+	'\uf270': {"aws", "aws"},
+	'\uf330': {"codeberg", "codeberg"},
+	// languages and tools
+	'\ue718':     {"node", "node"},
+	'\ue71e':     {"npm", "npm"},
+	'\U000f02c1': {"pnpm", "pnpm"},
+	'\U000f011b': {"yarn", "yarn"},
+	'\U000f0cd3': {"bun", "bun"},
+	'\ue7c0':     {"deno", "deno"},
+	'\ue626':     {"go", "go"},
+	'\ue235':     {"py", "py"},
+	'\uf487':     {"pkg", "pkg"},
+	'\uf4de':     {"→", "->"},
+	'\uebd8':     {"azure", "azure"},
+	'\U000f0833': {"helm", "helm"},
+	'\U000f10fe': {"k8s", "k8s"},
+	'\U000f1062': {"tf", "tf"},
+	'\uf308':     {"docker", "docker"},
+	'\uf4b7':     {"env", "env"},
+	// operating systems
+	'\ue62a': {"windows", "windows"},
+	'\uf179': {"macos", "macos"},
+	'\uf17c': {"linux", "linux"},
+	'\uf17b': {"android", "android"},
+	'\uf300': {"alpine", "alpine"},
+	'\uf301': {"aosc", "aosc"},
+	'\uf303': {"arch", "arch"},
+	'\uf304': {"centos", "centos"},
+	'\uf305': {"coreos", "coreos"},
+	'\uf306': {"debian", "debian"},
+	'\uf307': {"devuan", "devuan"},
+	'\uf309': {"elementary", "elementary"},
+	'\uf30a': {"fedora", "fedora"},
+	'\uf30d': {"gentoo", "gentoo"},
+	'\uf30e': {"mint", "mint"},
+	'\uf310': {"mageia", "mageia"},
+	'\uf312': {"manjaro", "manjaro"},
+	'\uf313': {"nix", "nix"},
+	'\uf314': {"opensuse", "opensuse"},
+	'\uf315': {"raspbian", "raspbian"},
+	'\uf316': {"redhat", "redhat"},
+	'\uf317': {"sabayon", "sabayon"},
+	'\uf319': {"slackware", "slackware"},
+	'\uf31b': {"ubuntu", "ubuntu"},
+	'\uf31d': {"alma", "alma"},
+	'\uf321': {"deepin", "deepin"},
+	'\uf322': {"endeavouros", "endeavouros"},
+	'\uf32b': {"rocky", "rocky"},
+}
+
+// the symbols used by segments and themes that fonts without box drawing or dingbats lack
+var asciiGlyphs = map[rune]string{
+	'↑': "^",
+	'↓': "v",
+	'←': "<-",
+	'→': "->",
+	'≡': "=",
+	'≢': "!=",
+	'❯': ">",
+	'❮': "<",
+	'➜': "->",
+	'›': ">",
+	'‹': "<",
+	'»': ">>",
+	'«': "<<",
+	'✓': "ok",
+	'✔': "ok",
+	'✗': "x",
+	'✘': "x",
+	'•': "*",
+	'●': "*",
+	'…': "...",
+	'─': "-",
+	'│': "|",
+	'╭': "+",
+	'╰': "+",
+	'├': "+",
+	'└': "+",
+	'┌': "+",
+}
+
+// ParseGlyphSet validates a glyph set set by the user
+func ParseGlyphSet(value string) (GlyphSet, bool) {
+	switch GlyphSet(strings.ToLower(strings.TrimSpace(value))) {
+	case AutoGlyphs, "":
+		return AutoGlyphs, true
+	case NerdGlyphs:
+		return NerdGlyphs, true
+	case UnicodeGlyphs:
+		return UnicodeGlyphs, true
+	case ASCIIGlyphs:
+		return ASCIIGlyphs, true
+	default:
+		return "", false
+	}
+}
+
+// OverrideGlyphs replaces the detected glyph set with the first of the given values that is set and isn't auto.
+func OverrideGlyphs(values ...string) {
+	for _, value := range values {
+		if len(value) == 0 {
+			continue
+		}
+
+		glyphs, OK := ParseGlyphSet(value)
+		if !OK {
+			log.Error(fmt.Errorf("unknown glyph set: %s", value))
+			continue
+		}
+
+		if glyphs == AutoGlyphs {
+			return
+		}
+
+		Glyphs = glyphs
+		return
+	}
+}
+
+// detectGlyphs falls back to ASCII on terminals which can't load a patched font, like the Linux console
+func detectGlyphs() GlyphSet {
+	switch strings.ToLower(os.Getenv("TERM")) {
+	case "linux", "dumb", "vt100", "vt102", "vt220":
+		return ASCIIGlyphs
+	default:
+		return NerdGlyphs
+	}
+}
+
+// fallbackGlyph returns the replacement for a character the glyph set can't display
+func fallbackGlyph(char rune) (string, bool) {
+	if Glyphs == NerdGlyphs || char <= unicode.MaxASCII {
+		return "", false
+	}
+
+	if nerd, OK := nerdGlyphs[char]; OK {
+		if Glyphs == ASCIIGlyphs {
+			return nerd.ascii, true
+		}
+
+		return nerd.unicode, true
+	}
+
+	// there's no way to know what an unknown icon looks like, leave it out
+	if unicode.Is(unicode.Co, char) {
+		return "", true
+	}
+
+	if Glyphs != ASCIIGlyphs {
+		return "", false
+	}
+
+	replacement, OK := asciiGlyphs[char]
+	return replacement, OK
+}
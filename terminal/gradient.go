@@ -0,0 +1,220 @@
+package terminal
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/log"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	gradientStart = "<gradient:"
+	colorEnd      = "</>"
+)
+
+// expandGradients replaces every <gradient:from:to>text</> anchor with a color anchor per character,
+// interpolating the colors over the width of the text. More than two colors can be used as stops.
+// Without true color support, the text gets the color in the middle of the gradient.
+func expandGradients(text string) string {
+	var builder strings.Builder
+
+	for {
+		start := strings.Index(text, gradientStart)
+		if start == -1 {
+			builder.WriteString(text)
+			return builder.String()
+		}
+
+		builder.WriteString(text[:start])
+
+		rest := text[start+len(gradientStart):]
+
+		end := strings.Index(rest, ">")
+		if end == -1 {
+			builder.WriteString(text[start:])
+			return builder.String()
+		}
+
+		content, remainder := splitGradientContent(rest[end+1:])
+		builder.WriteString(writeGradient(parseGradientStops(rest[:end]), content))
+
+		text = remainder
+	}
+}
+
+// parseGradientStops splits the colors on a colon, keeping palette references together
+func parseGradientStops(value string) []color.Ansi {
+	var stops []color.Ansi
+
+	parts := strings.Split(value, ":")
+
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+
+		if part == "p" && i+1 < len(parts) {
+			part += ":" + parts[i+1]
+			i++
+		}
+
+		stops = append(stops, color.Ansi(strings.TrimSpace(part)))
+	}
+
+	return stops
+}
+
+// splitGradientContent returns the text up to the end of the gradient and what comes after it.
+// Color anchors inside the gradient have their own end, style anchors and hyperlinks don't count.
+func splitGradientContent(text string) (string, string) {
+	depth := 0
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+
+		end := strings.Index(text[i:], ">")
+		if end == -1 {
+			break
+		}
+
+		tag := text[i : i+end+1]
+
+		switch {
+		case tag == colorEnd && depth == 0:
+			return text[:i], text[i+len(colorEnd):]
+		case tag == colorEnd:
+			depth--
+		case isColorAnchor(tag):
+			depth++
+		}
+
+		i += end
+	}
+
+	return text, ""
+}
+
+func isColorAnchor(tag string) bool {
+	if strings.HasPrefix(tag, "</") || tag == empty {
+		return false
+	}
+
+	switch tag {
+	case hyperLinkStart, hyperLinkText:
+		return false
+	}
+
+	for _, style := range knownStyles {
+		if tag == style.AnchorStart {
+			return false
+		}
+	}
+
+	// underline styles can have a color, but it doesn't change the foreground
+	if _, _, _, OK := parseUnderline(tag); OK {
+		return false
+	}
+
+	return true
+}
+
+type gradientToken struct {
+	text    string
+	isTag   bool
+	visible bool
+}
+
+func tokenizeGradient(text string) ([]*gradientToken, int) {
+	var tokens []*gradientToken
+	var width int
+	var inURL bool
+
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '<' {
+			if end := slices.Index(runes[i:], '>'); end != -1 {
+				tag := string(runes[i : i+end+1])
+
+				switch tag {
+				case hyperLinkStart:
+					inURL = true
+				case hyperLinkText:
+					inURL = false
+				}
+
+				tokens = append(tokens, &gradientToken{text: tag, isTag: true})
+				i += end
+				continue
+			}
+		}
+
+		token := &gradientToken{text: string(runes[i]), visible: !inURL}
+		if token.visible {
+			width += runewidth.RuneWidth(runes[i])
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, width
+}
+
+func writeGradient(stops []color.Ansi, content string) string {
+	tokens, width := tokenizeGradient(content)
+	if width == 0 {
+		return content
+	}
+
+	resolve := func(value color.Ansi) (color.Ansi, error) {
+		if Colors == nil {
+			return value, nil
+		}
+
+		return Colors.Resolve(value)
+	}
+
+	colors, err := color.Gradient(stops, width, resolve)
+	if err != nil {
+		log.Error(err)
+		return content
+	}
+
+	if color.CurrentProfile != color.TrueColor {
+		return "<" + string(colors[width/2]) + ">" + content + colorEnd
+	}
+
+	var builder strings.Builder
+	var column, depth int
+
+	for _, token := range tokens {
+		switch {
+		case token.isTag:
+			if token.text == colorEnd {
+				depth--
+			} else if isColorAnchor(token.text) {
+				depth++
+			}
+
+			builder.WriteString(token.text)
+			continue
+		case !token.visible:
+			builder.WriteString(token.text)
+			continue
+		}
+
+		// keep the colors of nested anchors, and don't bother coloring spaces
+		if depth > 0 || token.text == " " {
+			builder.WriteString(token.text)
+		} else {
+			builder.WriteString("<" + string(colors[column]) + ">" + token.text + colorEnd)
+		}
+
+		column += runewidth.StringWidth(token.text)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,106 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/LNKLEO/OMP/color"
+	"github.com/LNKLEO/OMP/log"
+)
+
+const (
+	underlineStart    = "\x1b[4m"
+	underlineEnd      = "\x1b[24m"
+	underlineColorEnd = "\x1b[59m"
+)
+
+// ExtendedUnderlines is set when the terminal supports the underline
+// styles and colors introduced by kitty, otherwise they fall back to a plain underline
+var ExtendedUnderlines bool
+
+// the anchors for the extended underline styles, which accept an optional color: <curly:p:red>text</curly>
+var underlineStyles = map[string]string{
+	"underline": "4",
+	"double":    "4:2",
+	"curly":     "4:3",
+	"dotted":    "4:4",
+	"dashed":    "4:5",
+}
+
+func supportsExtendedUnderlines() bool {
+	term := strings.ToLower(os.Getenv("TERM"))
+
+	switch {
+	case term == "xterm-kitty", term == "alacritty", term == "wezterm", term == "xterm-ghostty", term == "mintty", strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "contour"):
+		return true
+	case strings.HasPrefix(term, "screen"), strings.HasPrefix(term, "tmux"), term == "linux":
+		// multiplexers need explicit configuration to pass these through
+		return false
+	}
+
+	// VTE based terminals, like GNOME Terminal, since 0.52
+	if version, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && version >= 5200 {
+		return true
+	}
+
+	switch Program {
+	case "WezTerm", "iTerm.app", "ghostty", "vscode", WindowsTerminal:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeUnderline writes the sequence for an extended underline anchor like <curly:p:red> or </curly>,
+// it returns false when the anchor isn't one.
+func writeUnderline(anchor string) bool {
+	code, underlineColor, isEnd, OK := parseUnderline(anchor)
+	if !OK {
+		return false
+	}
+
+	if isEnd {
+		writeEscapedAnsiString(underlineEnd)
+
+		if ExtendedUnderlines {
+			writeEscapedAnsiString(underlineColorEnd)
+		}
+
+		return true
+	}
+
+	if !ExtendedUnderlines {
+		writeEscapedAnsiString(underlineStart)
+		return true
+	}
+
+	writeEscapedAnsiString(fmt.Sprintf(colorise, code))
+
+	if len(underlineColor) == 0 {
+		return true
+	}
+
+	sequence, err := color.UnderlineColor(color.Ansi(underlineColor), Colors.Resolve)
+	if err != nil {
+		log.Error(err)
+		return true
+	}
+
+	if len(sequence) != 0 {
+		writeEscapedAnsiString(fmt.Sprintf(colorise, sequence))
+	}
+
+	return true
+}
+
+// parseUnderline splits an extended underline anchor in the style's SGR code and its color
+func parseUnderline(anchor string) (code, underlineColor string, isEnd, OK bool) {
+	name := strings.TrimSuffix(strings.TrimPrefix(anchor, "<"), ">")
+	name, isEnd = strings.CutPrefix(name, "/")
+	name, underlineColor, _ = strings.Cut(name, ":")
+
+	code, OK = underlineStyles[name]
+	return code, underlineColor, isEnd, OK
+}
@@ -33,12 +33,15 @@ var (
 		{AnchorStart: `<d>`, AnchorEnd: `</d>`, Start: "\x1b[2m", End: "\x1b[22m"},
 		{AnchorStart: `<f>`, AnchorEnd: `</f>`, Start: "\x1b[5m", End: "\x1b[25m"},
 		{AnchorStart: `<r>`, AnchorEnd: `</r>`, Start: "\x1b[7m", End: "\x1b[27m"},
+		{AnchorStart: `<frame>`, AnchorEnd: `</frame>`, Start: "\x1b[51m", End: "\x1b[54m"},
+		{AnchorStart: `<circle>`, AnchorEnd: `</circle>`, Start: "\x1b[52m", End: "\x1b[54m"},
 	}
 
 	resetStyle      = &style{AnchorStart: "RESET", AnchorEnd: `</>`, End: "\x1b[0m"}
 	backgroundStyle = &style{AnchorStart: "BACKGROUND", AnchorEnd: `</>`, End: "\x1b[49m"}
 
 	BackgroundColor color.Ansi
+	Contrast        *color.Contrast
 	CurrentColors   *color.Set
 	ParentColors    []*color.Set
 	Colors          color.String
@@ -55,6 +58,7 @@ var (
 	runes           []rune
 
 	isTransparent bool
+	isText        bool
 	isInvisible   bool
 	isHyperlink   bool
 
@@ -104,7 +108,12 @@ func Init(sh string) {
 	log.Debug("terminal program:", Program)
 	log.Debug("terminal shell:", Shell)
 
-	color.TrueColor = Program != AppleTerminal
+	color.CurrentProfile = color.DetectProfile(os.Getenv, Program)
+	ExtendedUnderlines = supportsExtendedUnderlines()
+	Glyphs = detectGlyphs()
+
+	log.Debug("color profile:", string(color.CurrentProfile))
+	log.Debug("glyph set:", string(Glyphs))
 
 	formats = shell.GetFormats(Shell)
 }
@@ -256,11 +265,26 @@ func StopProgress() string {
 	return endProgress
 }
 
+// WriteText writes the text of a segment. Unlike separators and diamonds, which take
+// a background color as their foreground, its colors are checked for contrast.
+func WriteText(background, foreground color.Ansi, text string) {
+	isText = true
+	defer func() {
+		isText = false
+	}()
+
+	Write(background, foreground, text)
+}
+
 func Write(background, foreground color.Ansi, text string) {
 	if len(text) == 0 {
 		return
 	}
 
+	if strings.Contains(text, gradientStart) {
+		text = expandGradients(text)
+	}
+
 	backgroundColor, foregroundColor = asAnsiColors(background, foreground)
 
 	// default to white foreground
@@ -271,7 +295,7 @@ func Write(background, foreground color.Ansi, text string) {
 	// validate if we start with a color override
 	match := regex.FindNamedRegexMatch(AnchorRegex, text)
 	if len(match) != 0 && match[ANCHOR] != hyperLinkStart {
-		colorOverride := true
+		colorOverride := !writeUnderline(match[ANCHOR])
 		for _, style := range knownStyles {
 			if match[ANCHOR] != style.AnchorStart {
 				continue
@@ -392,6 +416,14 @@ func write(s rune) {
 		return
 	}
 
+	if replacement, OK := fallbackGlyph(s); OK {
+		for _, char := range replacement {
+			write(char)
+		}
+
+		return
+	}
+
 	// UNSOLVABLE: When "Interactive" is true, the prompt length calculation in Bash/Zsh can be wrong, since the final string expansion is done by shells.
 	length += runewidth.RuneWidth(s)
 	// length += utf8.RuneCountInString(string(s))
@@ -455,6 +487,10 @@ func writeArchorOverride(match map[string]string, background color.Ansi, i int)
 
 	position += len([]rune(match[ANCHOR])) - 1
 
+	if writeUnderline(match[ANCHOR]) {
+		return position
+	}
+
 	for _, style := range knownStyles {
 		if style.AnchorEnd == match[ANCHOR] {
 			writeEscapedAnsiString(style.End)
@@ -610,6 +646,10 @@ func asAnsiColors(background, foreground color.Ansi) (color.Ansi, color.Ansi) {
 		foreground = fg
 	}
 
+	if isText && Contrast != nil && foreground != color.Transparent {
+		foreground = Contrast.Check(foreground, background, Colors.Resolve)
+	}
+
 	inverted := foreground == color.Transparent && len(background) != 0
 
 	background = Colors.ToAnsi(background, !inverted)